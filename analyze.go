@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/bmoller/cherry-o/game/analysis"
+)
+
+/*
+runAnalyze handles the analyze command, which prints exact statistics for a ruleset and number of players.
+*/
+func runAnalyze(args []string) error {
+	flags := flag.NewFlagSet("cherry-o analyze", flag.ExitOnError)
+	var (
+		coverage = flags.Float64("coverage", 99, "list game lengths until this percentage of games are over")
+		players  = flags.Int("players", 4, "number of players in the game")
+	)
+	rules := addRuleFlags(flags)
+	flags.Parse(args)
+
+	r, err := rules()
+	if err != nil {
+		return err
+	}
+
+	report, err := analysis.Analyze(*players, r)
+	if err != nil {
+		return err
+	}
+
+	return writeAnalysis(os.Stdout, report, *coverage)
+}
+
+func writeAnalysis(w io.Writer, report analysis.Report, coverage float64) error {
+	out := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(out, "Exact analysis for %d players, first to %d cherries (%s)\n\n", report.Players, report.Rules.TargetScore, report.Rules.Overflow)
+	fmt.Fprintf(out, "Expected turns\t%.4f\n", report.ExpectedTurns)
+	fmt.Fprintf(out, "Expected rounds\t%.4f\n", report.ExpectedRounds)
+	fmt.Fprintf(out, "Median turns\t%d\n", report.Percentile(50))
+
+	fmt.Fprintln(out, "\nSeat\tWin chance\tvs. fair share")
+	fair := 1 / float64(report.Players)
+	for seat, chance := range report.SeatWins {
+		fmt.Fprintf(out, "%d\t%.4f%%\t%+.4f%%\n", seat+1, 100*chance, 100*(chance-fair))
+	}
+
+	fmt.Fprintln(out, "\nTurns\tChance\tOver by then")
+	last := report.Percentile(coverage)
+	var total float64
+	for turns := 1; turns <= last; turns++ {
+		total += report.Length[turns]
+		if report.Length[turns] == 0 {
+			continue
+		}
+		fmt.Fprintf(out, "%d\t%.4f%%\t%.4f%%\n", turns, 100*report.Length[turns], 100*total)
+	}
+
+	return out.Flush()
+}
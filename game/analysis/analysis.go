@@ -0,0 +1,168 @@
+/*
+Package analysis computes exact statistics for Cherry-O by treating each player's cherry count as a Markov chain.
+
+Every spin is independent and each player's count only changes on their own turn, so a player's progress
+is a chain over the counts 0 through the target score, with the winning counts absorbing.
+The distribution of how many of their own turns a player needs to win then fixes everything else:
+game length, and the chance that each seat is the first to finish.
+*/
+package analysis
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bmoller/cherry-o/game"
+)
+
+const (
+	// Tolerance is the probability mass left unaccounted for when a report is finished.
+	Tolerance = 1e-12
+	// MaxTurns caps how many turns per player are considered before giving up on a game that may never end.
+	MaxTurns = 100000
+)
+
+// Report holds the exact results for a number of players under a ruleset.
+type Report struct {
+	Players int
+	Rules   game.Ruleset
+	// Probability that a single player first wins on their k-th own turn, indexed by k.
+	SoloTurns []float64
+	// Probability that the game ends on its n-th turn overall, indexed by n.
+	Length []float64
+	// Probability that each seat wins, starting with the first player.
+	SeatWins []float64
+	// Expected number of turns overall, counting every player's spin.
+	ExpectedTurns float64
+	// Expected number of rounds, where the last round may be partial.
+	ExpectedRounds float64
+	// Probability mass beyond the longest game considered; at most Tolerance.
+	Truncated float64
+}
+
+/*
+SoloTurns returns the probability mass function of the number of turns a lone player needs to win under rules.
+The result is indexed by turn count and ends once less than Tolerance of the mass remains.
+*/
+func SoloTurns(rules game.Ruleset) ([]float64, error) {
+	if err := rules.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid ruleset: %s", err)
+	}
+
+	// states are the cherry counts that haven't won yet; every face is equally likely
+	var (
+		faceChance = 1 / float64(len(rules.Faces))
+		pmf        = []float64{0}
+		remaining  = 1.0
+		states     = make([]float64, rules.TargetScore)
+	)
+	states[0] = 1
+
+	for turn := 1; remaining >= Tolerance; turn++ {
+		if turn > MaxTurns {
+			return nil, fmt.Errorf("%.3g of games are still going after %d turns each; the game may never end", remaining, MaxTurns)
+		}
+
+		var (
+			next = make([]float64, len(states))
+			won  float64
+		)
+		for cherries, chance := range states {
+			if chance == 0 {
+				continue
+			}
+			for _, face := range rules.Faces {
+				if after := rules.Apply(cherries, face); rules.Won(after) {
+					won += chance * faceChance
+				} else {
+					next[after] += chance * faceChance
+				}
+			}
+		}
+
+		pmf = append(pmf, won)
+		remaining -= won
+		states = next
+	}
+
+	return pmf, nil
+}
+
+/*
+Analyze computes the exact game length distribution and the chance of winning from each seat
+for a game of players under rules.
+*/
+func Analyze(players int, rules game.Ruleset) (Report, error) {
+	if players <= 0 {
+		return Report{}, errors.New("need at least one player to analyze")
+	}
+
+	solo, err := SoloTurns(rules)
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{
+		Players:   players,
+		Rules:     rules,
+		SoloTurns: solo,
+		Length:    make([]float64, (len(solo)-1)*players+1),
+		SeatWins:  make([]float64, players),
+	}
+
+	// survival[k] is the chance that a player hasn't won within k of their own turns
+	survival := make([]float64, len(solo))
+	survival[0] = 1
+	for k := 1; k < len(solo); k++ {
+		survival[k] = survival[k-1] - solo[k]
+	}
+
+	var total float64
+	for round := 1; round < len(solo); round++ {
+		for seat := 0; seat < players; seat++ {
+			// earlier seats have had this round's turn already; later seats have not
+			chance := solo[round] *
+				pow(survival[round], seat) *
+				pow(survival[round-1], players-1-seat)
+			turns := (round-1)*players + seat + 1
+
+			report.Length[turns] = chance
+			report.SeatWins[seat] += chance
+			report.ExpectedTurns += chance * float64(turns)
+			report.ExpectedRounds += chance * float64(round)
+			total += chance
+		}
+	}
+	report.Truncated = 1 - total
+
+	return report, nil
+}
+
+func pow(base float64, exponent int) float64 {
+	result := 1.0
+	for i := 0; i < exponent; i++ {
+		result *= base
+	}
+	return result
+}
+
+// Cumulative returns the chance that the game is over within turns turns.
+func (r Report) Cumulative(turns int) float64 {
+	var total float64
+	for n := 0; n <= turns && n < len(r.Length); n++ {
+		total += r.Length[n]
+	}
+	return total
+}
+
+// Percentile returns the smallest game length, in turns, within which the game is over with chance at least p percent.
+func (r Report) Percentile(p float64) int {
+	var total float64
+	for n, chance := range r.Length {
+		total += chance
+		if total >= p/100 {
+			return n
+		}
+	}
+	return len(r.Length) - 1
+}
@@ -0,0 +1,135 @@
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/bmoller/cherry-o/game"
+	"github.com/bmoller/cherry-o/game/sim"
+)
+
+func TestSoloTurnsDeterministic(t *testing.T) {
+	rules := game.Ruleset{
+		TargetScore: 3,
+		Faces:       []game.Face{game.PickFace(1)},
+		MaxPlayers:  4,
+	}
+
+	pmf, err := SoloTurns(rules)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if fmt.Sprint(pmf) != "[0 0 0 1]" {
+		t.Fatalf("expected a certain win on turn 3 but got %v", pmf)
+	}
+}
+
+func TestSoloTurnsNeverEnds(t *testing.T) {
+	rules := game.Ruleset{
+		TargetScore: 3,
+		Faces:       []game.Face{game.PickFace(2)},
+		Overflow:    game.Bounce,
+		MaxPlayers:  4,
+	}
+
+	if _, err := SoloTurns(rules); err == nil {
+		t.Fatal("a game that can't be won exactly should be reported")
+	}
+}
+
+func TestAnalyzeDeterministic(t *testing.T) {
+	rules := game.Ruleset{
+		TargetScore: 3,
+		Faces:       []game.Face{game.PickFace(1)},
+		MaxPlayers:  4,
+	}
+
+	report, err := Analyze(3, rules)
+	switch {
+	case err != nil:
+		t.Fatalf("unexpected error: %s", err)
+	case report.SeatWins[0] != 1 || report.SeatWins[1] != 0 || report.SeatWins[2] != 0:
+		t.Fatalf("the first seat should always win; got %v", report.SeatWins)
+	case report.ExpectedTurns != 7:
+		t.Fatalf("expected the game to take 7 turns but got %f", report.ExpectedTurns)
+	case report.ExpectedRounds != 3:
+		t.Fatalf("expected the game to take 3 rounds but got %f", report.ExpectedRounds)
+	case report.Percentile(50) != 7:
+		t.Fatalf("expected a median of 7 turns but got %d", report.Percentile(50))
+	}
+}
+
+func TestAnalyzeInvalid(t *testing.T) {
+	if _, err := Analyze(0, game.StandardRules()); err == nil {
+		t.Fatal("shouldn't be able to analyze a game without players")
+	}
+	if _, err := Analyze(2, game.Ruleset{}); err == nil {
+		t.Fatal("shouldn't be able to analyze an invalid ruleset")
+	}
+}
+
+func TestAnalyzeTotals(t *testing.T) {
+	for _, overflow := range []game.Overflow{game.Clamp, game.Bounce, game.Overshoot} {
+		for players := 1; players <= 6; players++ {
+			t.Run(fmt.Sprintf("%s %d players", overflow, players), func(t *testing.T) {
+				rules := game.StandardRules()
+				rules.Overflow = overflow
+
+				report, err := Analyze(players, rules)
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				var seats, lengths float64
+				for _, chance := range report.SeatWins {
+					seats += chance
+				}
+				for _, chance := range report.Length {
+					lengths += chance
+				}
+				switch {
+				case math.Abs(seats-1) > 1e-9:
+					t.Fatalf("seat chances should add up to 1 but got %f", seats)
+				case math.Abs(lengths-1) > 1e-9:
+					t.Fatalf("length chances should add up to 1 but got %f", lengths)
+				case report.Truncated > 1e-9:
+					t.Fatalf("too much probability left over: %g", report.Truncated)
+				}
+				for seat := 1; seat < players; seat++ {
+					if report.SeatWins[seat] >= report.SeatWins[seat-1] {
+						t.Fatalf("seat %d shouldn't be better off than seat %d", seat+1, seat)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestAnalyzeMatchesSimulation(t *testing.T) {
+	rules := game.StandardRules()
+	report, err := Analyze(4, rules)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	result, err := sim.Run(context.Background(), sim.Config{
+		Players: 4,
+		Games:   20000,
+		Rules:   rules,
+		Seed:    8,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if diff := math.Abs(report.ExpectedTurns - result.Turns.Mean()); diff > 1 {
+		t.Fatalf("expected about %.2f turns but simulation averaged %.2f", report.ExpectedTurns, result.Turns.Mean())
+	}
+	for seat, chance := range report.SeatWins {
+		if diff := math.Abs(chance - result.WinRate(seat)); diff > 0.02 {
+			t.Fatalf("expected seat %d to win %.3f of games but simulation gave %.3f", seat+1, chance, result.WinRate(seat))
+		}
+	}
+}
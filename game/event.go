@@ -0,0 +1,121 @@
+package game
+
+// Event is something that happened in a Game; subscribers receive one of the concrete types below.
+type Event interface {
+	event()
+}
+
+type PlayerAdded struct {
+	Player Player `json:"player"`
+}
+
+type PlayerRemoved struct {
+	Player Player `json:"player"`
+}
+
+// PlayerUpdated is sent when a player is renamed or recolored; Previous is how they were before.
+type PlayerUpdated struct {
+	Player   Player `json:"player"`
+	Previous Player `json:"previous"`
+}
+
+// SeatsChanged is sent when players change seats; Players is the roster in its new seat order.
+type SeatsChanged struct {
+	Players []Player `json:"players"`
+}
+
+type TurnTaken struct {
+	// Number counts turns from 1 across all players.
+	Number int  `json:"number"`
+	Turn   Turn `json:"turn"`
+}
+
+// CherriesLost is sent when a turn leaves a player with fewer cherries, other than by spilling the bucket.
+type CherriesLost struct {
+	Player Player
+	Face   Face
+	Amount int
+}
+
+type BucketSpilled struct {
+	Player Player
+	Amount int
+}
+
+// LeadChanged is sent when a different player takes the outright lead; Previous is the zero Player at the first lead.
+type LeadChanged struct {
+	Leader   Player
+	Previous Player
+}
+
+type GameWon struct {
+	Winner Player `json:"winner"`
+	Turns  int    `json:"turns"`
+}
+
+// GameReset is sent when a game is reset to play again with the same players.
+type GameReset struct{}
+
+func (PlayerAdded) event()   {}
+func (PlayerRemoved) event() {}
+func (PlayerUpdated) event() {}
+func (SeatsChanged) event()  {}
+func (TurnTaken) event()     {}
+func (CherriesLost) event()  {}
+func (BucketSpilled) event() {}
+func (LeadChanged) event()   {}
+func (GameWon) event()       {}
+func (GameReset) event()     {}
+
+// Subscriber receives a Game's events synchronously, in the order they happen.
+type Subscriber interface {
+	Notify(Event)
+}
+
+// SubscriberFunc adapts a function to the Subscriber interface.
+type SubscriberFunc func(Event)
+
+func (f SubscriberFunc) Notify(e Event) {
+	f(e)
+}
+
+type channelSubscriber chan<- Event
+
+func (c channelSubscriber) Notify(e Event) {
+	c <- e
+}
+
+// ChannelSubscriber delivers events to ch; the game blocks until each event is received, so ch should be buffered or drained promptly.
+func ChannelSubscriber(ch chan<- Event) Subscriber {
+	return channelSubscriber(ch)
+}
+
+// Subscribe returns a copy of g that also notifies s of its events.
+func (g Game) Subscribe(s Subscriber) Game {
+	g.subscribers = append(append([]Subscriber(nil), g.subscribers...), s)
+	return g
+}
+
+func (g Game) emit(e Event) {
+	for _, s := range g.subscribers {
+		s.Notify(e)
+	}
+}
+
+// outrightLeader returns the seat of the only player holding the most cherries, or -1 if nobody leads alone.
+func (g Game) outrightLeader() int {
+	leader, most, tied := -1, 0, false
+	for i, player := range g.players {
+		switch {
+		case player.cherries > most:
+			leader, most, tied = i, player.cherries, false
+		case player.cherries == most && most > 0:
+			tied = true
+		}
+	}
+	if tied {
+		return -1
+	}
+
+	return leader
+}
@@ -0,0 +1,155 @@
+package game
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func eventNames(events []Event) []string {
+	names := make([]string, len(events))
+	for i, e := range events {
+		names[i] = reflect.TypeOf(e).Name()
+	}
+	return names
+}
+
+func TestGameEventsRoster(t *testing.T) {
+	var events []Event
+	g := Game{}.Subscribe(SubscriberFunc(func(e Event) {
+		events = append(events, e)
+	}))
+
+	g, _ = g.AddPlayer("Lori", Blue)
+	g, _ = g.AddPlayer("Lori", Green) // rejected; no event
+	g, _ = g.AddPlayer("Clinton", Yellow)
+	g, _ = g.UpdatePlayer(g.Players()[1].ID(), "Lori", Green) // rejected; no event
+	g, _ = g.UpdatePlayer(g.Players()[1].ID(), "Clint", Green)
+	g, _ = g.MovePlayer(g.Players()[1].ID(), -1)
+	g, _ = g.MovePlayer(g.Players()[1].ID(), 1) // rejected; no event
+	g, _ = g.RemovePlayer(g.Players()[1].ID())
+
+	expected := []string{"PlayerAdded", "PlayerAdded", "PlayerUpdated", "SeatsChanged", "PlayerRemoved"}
+	if actual := eventNames(events); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %v but got %v", expected, actual)
+	}
+	if updated := events[2].(PlayerUpdated); updated.Previous.Name != "Clinton" || updated.Player.Name != "Clint" {
+		t.Fatalf("expected Clinton to be renamed Clint but got %s renamed %s", updated.Previous.Name, updated.Player.Name)
+	}
+	if seated := events[3].(SeatsChanged).Players; seated[0].Name != "Clint" || seated[1].Name != "Lori" {
+		t.Fatalf("expected Clint then Lori but got %s then %s", seated[0].Name, seated[1].Name)
+	}
+	if removed := events[4].(PlayerRemoved).Player; removed.Name != "Lori" {
+		t.Fatalf("expected Lori to be removed but got %s", removed.Name)
+	}
+}
+
+func TestGameEventsPlay(t *testing.T) {
+	testCases := []struct {
+		spins    fixedSpinner
+		expected []string
+	}{
+		{
+			// Lori +4, Clinton +1, Lori +4, Clinton spills 1, Lori +2 and wins
+			spins: fixedSpinner{3, 0, 3, 6, 1},
+			expected: []string{
+				"TurnTaken", "LeadChanged",
+				"TurnTaken",
+				"TurnTaken",
+				"TurnTaken", "BucketSpilled",
+				"TurnTaken", "GameWon",
+			},
+		},
+		{
+			// Lori +2, Clinton +4, Lori bird, Clinton dog, Lori +4, Clinton +4, Lori +4, Clinton +4 and wins
+			spins: fixedSpinner{1, 3, 4, 5, 3, 3, 3, 3},
+			expected: []string{
+				"TurnTaken", "LeadChanged",
+				"TurnTaken", "LeadChanged",
+				"TurnTaken", "CherriesLost",
+				"TurnTaken", "CherriesLost",
+				"TurnTaken", "LeadChanged",
+				"TurnTaken", "LeadChanged",
+				"TurnTaken", "LeadChanged",
+				"TurnTaken", "LeadChanged", "GameWon",
+			},
+		},
+	}
+
+	for i, test := range testCases {
+		t.Run(fmt.Sprintf("game %d", i+1), func(t *testing.T) {
+			g := Game{}
+			g, _ = g.AddPlayer("Lori", Blue)
+			g, _ = g.AddPlayer("Clinton", Yellow)
+
+			events := make(chan Event, 100)
+			spins := test.spins
+			if _, _, err := g.WithSpinner(&spins).Subscribe(ChannelSubscriber(events)).Play(); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			close(events)
+
+			var received []Event
+			for e := range events {
+				received = append(received, e)
+			}
+			if actual := eventNames(received); !reflect.DeepEqual(actual, test.expected) {
+				t.Fatalf("expected %v but got %v", test.expected, actual)
+			}
+			for n, e := range received {
+				if taken, ok := e.(TurnTaken); ok && taken.Number < 1 {
+					t.Fatalf("event %d has an invalid turn number %d", n, taken.Number)
+				}
+			}
+		})
+	}
+}
+
+func TestGameEventsDetails(t *testing.T) {
+	g := Game{}
+	g, _ = g.AddPlayer("Lori", Blue)
+	g, _ = g.AddPlayer("Clinton", Yellow)
+
+	var events []Event
+	spins := fixedSpinner{1, 3, 4, 3}
+	g = g.WithSpinner(&spins).Subscribe(SubscriberFunc(func(e Event) {
+		events = append(events, e)
+	}))
+	for i := 0; i < 4; i++ {
+		g, _, _, _ = g.NextTurn()
+	}
+
+	var leads []LeadChanged
+	for _, e := range events {
+		switch e := e.(type) {
+		case CherriesLost:
+			if e.Player.Name != "Lori" || e.Amount != 2 || e.Face.ID != BirdID {
+				t.Fatalf("expected Lori to lose 2 cherries to the bird but got %+v", e)
+			}
+		case LeadChanged:
+			leads = append(leads, e)
+		}
+	}
+
+	if len(leads) != 2 {
+		t.Fatalf("expected 2 lead changes but got %d", len(leads))
+	}
+	if leads[0].Leader.Name != "Lori" || leads[0].Previous.ID() != 0 {
+		t.Fatalf("expected Lori to take the first lead but got %+v", leads[0])
+	}
+	if leads[1].Leader.Name != "Clinton" || leads[1].Previous.Name != "Lori" {
+		t.Fatalf("expected Clinton to take the lead from Lori but got %+v", leads[1])
+	}
+}
+
+func TestGameSubscribeCopies(t *testing.T) {
+	var a, b int
+	base := Game{}.Subscribe(SubscriberFunc(func(Event) { a++ }))
+	withB := base.Subscribe(SubscriberFunc(func(Event) { b++ }))
+
+	base.AddPlayer("Lori", Blue)
+	withB.AddPlayer("Lori", Blue)
+	if a != 2 || b != 1 {
+		t.Fatalf("subscribing a copy shouldn't affect the original; got %d and %d notifications", a, b)
+	}
+}
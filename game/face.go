@@ -0,0 +1,78 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FaceID identifies a spinner face independently of its display name or effect.
+type FaceID string
+
+const (
+	BirdID   FaceID = "bird"
+	DogID    FaceID = "dog"
+	BucketID FaceID = "bucket"
+)
+
+// Face is a single segment of the spinner.
+type Face struct {
+	ID     FaceID `json:"id"`
+	Name   string `json:"name"`
+	Symbol string `json:"symbol"`
+	// Effect is the number of cherries picked, or lost when negative.
+	Effect int `json:"effect"`
+	// Spill empties the bucket entirely; Effect is ignored.
+	Spill bool `json:"spill,omitempty"`
+}
+
+var (
+	BirdFace = Face{
+		ID:     BirdID,
+		Name:   "bird",
+		Symbol: "🐦",
+		Effect: -2,
+	}
+	DogFace = Face{
+		ID:     DogID,
+		Name:   "dog",
+		Symbol: "🐕",
+		Effect: -2,
+	}
+	BucketFace = Face{
+		ID:     BucketID,
+		Name:   "spilled bucket",
+		Symbol: "🪣",
+		Spill:  true,
+	}
+)
+
+// PickFace returns the face for picking count cherries from the tree.
+func PickFace(count int) Face {
+	name := fmt.Sprintf("%d cherries", count)
+	if count == 1 {
+		name = "1 cherry"
+	}
+
+	return Face{
+		ID:     FaceID(fmt.Sprintf("pick-%d", count)),
+		Name:   name,
+		Symbol: strings.Repeat("🍒", count),
+		Effect: count,
+	}
+}
+
+// amount returns the change in cherries this face causes for a player currently holding cherries.
+func (f Face) amount(cherries int) int {
+	if f.Spill {
+		return -cherries
+	}
+	return f.Effect
+}
+
+func (f Face) String() string {
+	return f.Name
+}
+
+func (f Face) FilterValue() string {
+	return f.Name
+}
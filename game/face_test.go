@@ -0,0 +1,65 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPickFace(t *testing.T) {
+	testCases := map[int]string{
+		1: "1 cherry",
+		2: "2 cherries",
+		4: "4 cherries",
+	}
+
+	for count, name := range testCases {
+		t.Run(name, func(t *testing.T) {
+			face := PickFace(count)
+			switch {
+			case face.ID != FaceID(fmt.Sprintf("pick-%d", count)):
+				t.Fatalf("unexpected ID %s", face.ID)
+			case face.Name != name:
+				t.Fatalf("expected name %s but got %s", name, face.Name)
+			case face.Effect != count:
+				t.Fatalf("expected effect %d but got %d", count, face.Effect)
+			case face.Spill:
+				t.Fatal("picking cherries shouldn't spill the bucket")
+			}
+		})
+	}
+}
+
+func TestStandardFacesDistinct(t *testing.T) {
+	seen := make(map[FaceID]bool)
+	for _, face := range standardFaces {
+		if seen[face.ID] {
+			t.Fatalf("face %s appears more than once", face.ID)
+		}
+		seen[face.ID] = true
+	}
+	if BirdFace == DogFace {
+		t.Fatal("the bird and dog faces should be distinguishable")
+	}
+}
+
+func TestFaceAmount(t *testing.T) {
+	testCases := []struct {
+		face     Face
+		cherries int
+		expected int
+	}{
+		{PickFace(3), 4, 3},
+		{BirdFace, 4, -2},
+		{DogFace, 1, -2},
+		{BucketFace, 0, 0},
+		{BucketFace, 7, -7},
+	}
+
+	for _, test := range testCases {
+		t.Run(fmt.Sprintf("%s with %d", test.face, test.cherries), func(t *testing.T) {
+			if actual := test.face.amount(test.cherries); actual != test.expected {
+				t.Fatalf("expected %d but got %d", test.expected, actual)
+			}
+		})
+	}
+}
@@ -1,158 +1,506 @@
 package game
 
 import (
-	"crypto/rand"
 	"errors"
 	"fmt"
-	"math/big"
+	"sort"
+	"strings"
+	"time"
 )
 
+// Defaults used by StandardRules.
 const (
 	MaxPlayers   = 4
 	WinningScore = 10
 )
 
 var (
-	spinnerValues = [7]int{1, 2, 3, 4, -2, -2, -10}
+	standardFaces = [7]Face{PickFace(1), PickFace(2), PickFace(3), PickFace(4), BirdFace, DogFace, BucketFace}
 )
 
+// Phase describes where a Game is in its lifecycle; players may only be added, removed, changed, or reseated during Setup.
+type Phase int
+
+const (
+	Setup Phase = iota
+	InProgress
+	Finished
+)
+
+func (p Phase) String() string {
+	switch p {
+	case Setup:
+		return "setup"
+	case InProgress:
+		return "in progress"
+	case Finished:
+		return "finished"
+	default:
+		return fmt.Sprintf("%d", int(p))
+	}
+}
+
 type Game struct {
-	playerCount int
-	players     [MaxPlayers]Player
+	clock   func() time.Time
+	current int
+	lastID  PlayerID
+	leader  PlayerID
+	// log is every change made to the game; the rest of its state is what the log adds up to, as kept by commit.
+	log         []Entry
+	phase       Phase
+	players     []Player
+	rules       Ruleset
+	spinner     Spinner
+	subscribers []Subscriber
+	turns       []Turn
+	// unlogged is set on the copy of a game that Play runs to the end; nobody could read its log, so none is kept.
+	unlogged bool
+	winner   int
+}
+
+// New returns an empty Game played under rules; the zero Game is also usable and plays by StandardRules.
+func New(rules Ruleset) (Game, error) {
+	if err := rules.Validate(); err != nil {
+		return Game{}, fmt.Errorf("invalid ruleset: %s", err)
+	}
+
+	return Game{rules: rules.clone()}, nil
+}
+
+func (g Game) Rules() Ruleset {
+	return g.activeRules().clone()
+}
+
+// activeRules returns the rules without copying them; callers must not modify the result.
+func (g Game) activeRules() Ruleset {
+	if g.rules.TargetScore == 0 {
+		return standardRules
+	}
+	return g.rules
+}
+
+// WithSpinner returns a copy of g that draws its spins from s; a nil s restores the default CryptoSpinner.
+func (g Game) WithSpinner(s Spinner) Game {
+	g.spinner = s
+	return g
+}
+
+func (g Game) Spinner() Spinner {
+	if g.spinner == nil {
+		return CryptoSpinner{}
+	}
+	return g.spinner
+}
+
+func (g Game) Phase() Phase {
+	return g.phase
+}
+
+// CurrentPlayer returns the player who will spin next, or the winner once the game is finished.
+func (g Game) CurrentPlayer() (Player, bool) {
+	if len(g.players) == 0 {
+		return Player{}, false
+	}
+	return g.players[g.current], true
+}
+
+func (g Game) Turns() []Turn {
+	return g.turns
+}
+
+func (g Game) Winner() (Player, bool) {
+	if g.phase != Finished {
+		return Player{}, false
+	}
+	return g.players[g.winner], true
 }
 
 func (g Game) PlayerCount() int {
-	return g.playerCount
+	return len(g.players)
 }
 
+// Players returns the players in seat order; the slice is a copy and may be modified freely.
 func (g Game) Players() []Player {
-	return g.players[:g.playerCount]
+	return g.clonePlayers()
+}
+
+// clonePlayers copies the roster so that a modified Game never shares players with the Game it was derived from.
+func (g Game) clonePlayers() []Player {
+	return append([]Player(nil), g.players...)
+}
+
+// seatedAs reports whether players are already sitting in g's seats in that order.
+func (g Game) seatedAs(players []Player) bool {
+	if len(players) != len(g.players) {
+		return false
+	}
+	for seat, player := range players {
+		if g.players[seat] != player {
+			return false
+		}
+	}
+	return true
 }
 
 func (g Game) AvailableColors() (availability map[Color]bool) {
-	availability = map[Color]bool{
-		Blue:   true,
-		Green:  true,
-		Red:    true,
-		Yellow: true,
+	availability = make(map[Color]bool, len(colors))
+	for _, color := range colors {
+		availability[color] = true
 	}
 
-	for i := 0; i < g.playerCount; i++ {
-		availability[g.players[i].color] = false
+	if !g.activeRules().ShareColors {
+		for _, player := range g.players {
+			availability[player.color] = false
+		}
 	}
 
 	return
 }
 
+// Player looks up a current player by ID.
+func (g Game) Player(id PlayerID) (Player, bool) {
+	if i := g.seatOf(id); i >= 0 {
+		return g.players[i], true
+	}
+	return Player{}, false
+}
+
+// seatOf returns the index of the player with id, or -1 if there is no such player.
+func (g Game) seatOf(id PlayerID) int {
+	for i, player := range g.players {
+		if player.id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (g Game) nameTaken(name string) bool {
+	for _, player := range g.players {
+		if strings.EqualFold(player.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
 func (g Game) AddPlayer(name string, color Color) (Game, error) {
+	g.own()
+	err := g.commit(PlayerAdded{Player{
+		Name:  name,
+		color: color,
+		id:    g.lastID + 1,
+	}})
+
+	return g, err
+}
+
+func (g Game) RemovePlayer(id PlayerID) (Game, error) {
 	var err error
 
-	switch {
-	case g.playerCount == len(g.players):
-		err = errors.New("max player count reached; unable to add a new player")
-	case name == "":
-		err = errors.New("must provide a valid name")
-	case !g.AvailableColors()[color]:
-		err = fmt.Errorf("the %s color is not available", color)
+	switch seat := g.seatOf(id); {
+	case g.phase != Setup:
+		err = errors.New("players can't be removed once the game has started")
+	case len(g.players) == 0:
+		err = errors.New("no players to remove")
+	case seat < 0:
+		err = fmt.Errorf("%s is not a current player", id)
 	default:
-		g.players[g.playerCount] = Player{
-			Name:  name,
-			color: color,
-		}
-		g.playerCount++
+		g.own()
+		err = g.commit(PlayerRemoved{g.players[seat]})
 	}
 
 	return g, err
 }
 
-func (g Game) RemovePlayer(name string) (Game, error) {
+// UpdatePlayer renames and recolors the player with id in their current seat.
+// The name and color are checked as AddPlayer checks them, except that the player's own may be kept.
+func (g Game) UpdatePlayer(id PlayerID, name string, color Color) (Game, error) {
 	var err error
 
-	switch {
-	case g.playerCount == 0:
-		err = errors.New("no players to remove")
-	case name == "":
-		err = errors.New("must provide a valid name")
+	switch seat := g.seatOf(id); {
+	case g.phase != Setup:
+		err = errors.New("players can't be changed once the game has started")
+	case seat < 0:
+		err = fmt.Errorf("%s is not a current player", id)
 	default:
-		var (
-			found   bool
-			players [MaxPlayers]Player
-		)
-
-		for i, j := 0, 0; i < g.playerCount; i++ {
-			if g.players[i].Name == name {
-				found = true
-			} else {
-				players[j] = g.players[i]
-				j++
-			}
+		updated := g.players[seat]
+		updated.Name = name
+		updated.color = color
+		g.own()
+		err = g.commit(PlayerUpdated{Player: updated, Previous: g.players[seat]})
+	}
+
+	return g, err
+}
+
+// SetPlayerAge records the age in years of the player with id; an age of 0 forgets it.
+func (g Game) SetPlayerAge(id PlayerID, age int) (Game, error) {
+	var err error
+
+	switch seat := g.seatOf(id); {
+	case g.phase != Setup:
+		err = errors.New("players can't be changed once the game has started")
+	case seat < 0:
+		err = fmt.Errorf("%s is not a current player", id)
+	case g.players[seat].age != age:
+		updated := g.players[seat]
+		updated.age = age
+		g.own()
+		err = g.commit(PlayerUpdated{Player: updated, Previous: g.players[seat]})
+	}
+
+	return g, err
+}
+
+// MovePlayer moves the player with id by offset seats, toward the first seat if offset is negative.
+// The players in between each shift over by one seat to make room.
+func (g Game) MovePlayer(id PlayerID, offset int) (Game, error) {
+	var err error
+
+	switch seat := g.seatOf(id); {
+	case g.phase != Setup:
+		err = errors.New("players can't change seats once the game has started")
+	case seat < 0:
+		err = fmt.Errorf("%s is not a current player", id)
+	case seat+offset < 0 || seat+offset >= len(g.players):
+		err = fmt.Errorf("%s can't move %d seats from seat %d", g.players[seat].Name, offset, seat+1)
+	case offset != 0:
+		moved := g.players[seat]
+		players := make([]Player, 0, len(g.players))
+		players = append(players, g.players[:seat]...)
+		players = append(players, g.players[seat+1:]...)
+		players = append(players[:seat+offset], append([]Player{moved}, players[seat+offset:]...)...)
+		g.own()
+		err = g.commit(SeatsChanged{players})
+	}
+
+	return g, err
+}
+
+// ShufflePlayers seats the players in a random order, drawn from g's spinner.
+// If the order drawn is the one they're already in, g is returned as it was.
+func (g Game) ShufflePlayers() (Game, error) {
+	if g.phase != Setup {
+		return g, errors.New("players can't change seats once the game has started")
+	}
+
+	players := g.clonePlayers()
+	spinner := g.Spinner()
+	for i := len(players) - 1; i > 0; i-- {
+		j, err := spinner.Spin(i + 1)
+		if err != nil {
+			return g, err
 		}
-		g.players = players
+		players[i], players[j] = players[j], players[i]
+	}
+	if g.seatedAs(players) {
+		return g, nil
+	}
+	g.own()
+	err := g.commit(SeatsChanged{players})
 
-		if !found {
-			err = fmt.Errorf("%s is not a current player", name)
-		} else {
-			g.playerCount--
+	return g, err
+}
+
+// SeatYoungestFirst seats the players from youngest to oldest, as the official rules have the youngest start.
+// Players whose age isn't known follow the rest, and players of the same age keep their order.
+// If they're seated that way already, g is returned as it was.
+func (g Game) SeatYoungestFirst() (Game, error) {
+	switch {
+	case g.phase != Setup:
+		return g, errors.New("players can't change seats once the game has started")
+	case len(g.players) == 0:
+		return g, errors.New("no players to seat")
+	}
+
+	players := g.clonePlayers()
+	sort.SliceStable(players, func(i, j int) bool {
+		switch a, b := players[i].age, players[j].age; {
+		case a == 0:
+			return false
+		case b == 0:
+			return true
+		default:
+			return a < b
 		}
+	})
+	if players[0].age == 0 {
+		return g, errors.New("no players have an age")
+	}
+	if g.seatedAs(players) {
+		return g, nil
 	}
+	g.own()
+	err := g.commit(SeatsChanged{players})
 
 	return g, err
 }
 
+// Reset returns a copy of g with the same players back in the Setup phase, ready for a new game.
+// The log carries on from the finished game, so the reset can itself be undone with At.
+func (g Game) Reset() Game {
+	g.own()
+	// a reset is always allowed, so there is no error to report
+	_ = g.commit(GameReset{})
+
+	return g
+}
+
+/*
+own gives g private copies of its players, turns, and log so that it can be advanced in place.
+Copies of g made before the call are unaffected by anything done to g afterwards.
+*/
+func (g *Game) own() {
+	g.players = g.clonePlayers()
+	g.turns = append([]Turn(nil), g.turns...)
+	g.log = append([]Entry(nil), g.log...)
+}
+
+/*
+advance spins for the current player and moves on to the next seat, modifying g in place.
+g must own its players, turns, and log; see own.
+*/
+func (g *Game) advance() (Turn, error) {
+	if err := g.playable(); err != nil {
+		return Turn{}, err
+	}
+
+	turn, _, err := takeTurn(g.players[g.current], g.activeRules(), g.Spinner())
+	if err != nil {
+		return Turn{}, err
+	}
+	if err = g.record(turn); err != nil {
+		return Turn{}, err
+	}
+
+	return turn, nil
+}
+
+/*
+apply takes the current player's turn with face instead of spinning, modifying g in place.
+g must own its players, turns, and log; see own.
+*/
+func (g *Game) apply(face Face) (Turn, error) {
+	if err := g.playable(); err != nil {
+		return Turn{}, err
+	}
+
+	rules := g.activeRules()
+	face, found := rules.face(face.ID)
+	if !found {
+		return Turn{}, fmt.Errorf("%q is not a face of this spinner", face.ID)
+	}
+
+	turn, _ := faceTurn(g.players[g.current], face, rules)
+	if err := g.record(turn); err != nil {
+		return Turn{}, err
+	}
+
+	return turn, nil
+}
+
+// playable returns an error if g can't take another turn.
+func (g Game) playable() error {
+	switch {
+	case len(g.players) == 0:
+		return errors.New("need at least one player to play")
+	case g.phase == Finished:
+		return errors.New("the game is already over")
+	}
+	return nil
+}
+
+/*
+record logs turn for the current player, and their win if it won, telling subscribers what else the turn changed.
+*/
+func (g *Game) record(turn Turn) error {
+	before, leader := g.players[g.current], g.leader
+	if err := g.commit(TurnTaken{len(g.turns) + 1, turn}); err != nil {
+		return err
+	}
+
+	switch lost := before.cherries - turn.Player.cherries; {
+	case turn.Face.Spill:
+		g.emit(BucketSpilled{turn.Player, lost})
+	case lost > 0:
+		g.emit(CherriesLost{turn.Player, turn.Face, lost})
+	}
+
+	if g.leader != leader {
+		current, _ := g.Player(g.leader)
+		previous, _ := g.Player(leader)
+		g.emit(LeadChanged{current, previous})
+	}
+
+	if g.activeRules().Won(turn.Player.cherries) {
+		return g.commit(GameWon{turn.Player, len(g.turns)})
+	}
+	return nil
+}
+
+/*
+NextTurn spins for the current player and advances to the next seat.
+The returned Game reflects the turn; over reports whether the turn won the game.
+*/
+func (g Game) NextTurn() (next Game, turn Turn, over bool, err error) {
+	g.own()
+	turn, err = g.advance()
+
+	return g, turn, g.phase == Finished, err
+}
+
+/*
+ApplyFace takes the current player's turn as though they had spun face, without using the spinner.
+It is meant for keeping score of a game played with a physical spinner; face must be one of the ruleset's faces, matched by ID.
+The results are the same as for NextTurn.
+*/
+func (g Game) ApplyFace(face Face) (next Game, turn Turn, over bool, err error) {
+	g.own()
+	turn, err = g.apply(face)
+
+	return g, turn, g.phase == Finished, err
+}
+
+/*
+Play runs g to completion from its current state, one turn at a time as NextTurn would.
+It returns every turn of the game, including any taken before Play was called, along with the winner.
+The finished game itself isn't returned, so no log is kept of the turns Play takes; this keeps simulations quick.
+*/
 func (g Game) Play() (turns []Turn, winner Player, err error) {
-	switch g.playerCount {
-	case 0:
-		err = errors.New("need at least one player to play")
-	default:
-		var (
-			turn     Turn
-			gameOver bool
-			i        int
-		)
-
-		for !gameOver {
-			for i = 0; i < g.playerCount; i++ {
-				turn, g.players[i], err = takeTurn(g.players[i])
-				if err != nil {
-					return
-				} else {
-					turns = append(turns, turn)
-				}
-				if g.players[i].cherries == WinningScore {
-					gameOver = true
-					winner = g.players[i]
-					break
-				}
-			}
+	g.own()
+	g.unlogged = true
+	for g.phase != Finished {
+		if _, err = g.advance(); err != nil {
+			return
 		}
 	}
 
-	return
+	winner, _ = g.Winner()
+
+	return g.Turns(), winner, err
 }
 
 type Turn struct {
-	Spin   int
+	Face   Face
 	Player Player
 }
 
-func takeTurn(player Player) (Turn, Player, error) {
-	var (
-		err  error
-		turn Turn
-	)
-
-	upperBound := big.NewInt(int64(len(spinnerValues)))
-	spin, err := rand.Int(rand.Reader, upperBound)
+func takeTurn(player Player, rules Ruleset, spinner Spinner) (Turn, Player, error) {
+	spin, err := spinner.Spin(len(rules.Faces))
+	if err == nil && (spin < 0 || spin >= len(rules.Faces)) {
+		err = fmt.Errorf("spinner returned out-of-range face %d", spin)
+	}
 	if err != nil {
-		err = fmt.Errorf("failed to generate a random number: %s", err)
-	} else {
-		value := spinnerValues[spin.Uint64()]
-		player = player.updateCherries(value)
-		turn = Turn{
-			Spin:   value,
-			Player: player,
-		}
+		return Turn{}, player, err
 	}
 
-	return turn, player, err
+	turn, player := faceTurn(player, rules.Faces[spin], rules)
+	return turn, player, nil
+}
+
+// faceTurn applies face to player's cherries under rules.
+func faceTurn(player Player, face Face, rules Ruleset) (Turn, Player) {
+	player = player.updateCherries(face.amount(player.cherries), rules)
+	return Turn{Face: face, Player: player}, player
 }
@@ -2,6 +2,7 @@ package game
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -29,17 +30,18 @@ var playerTestValues = []map[string]Color{
 
 func TestGamePlayerCountDirect(t *testing.T) {
 	testCases := map[int]int{
-		0: 0,
-		1: 1,
-		2: 2,
-		3: 3,
-		4: 4,
+		0:  0,
+		1:  1,
+		2:  2,
+		3:  3,
+		4:  4,
+		12: 12,
 	}
 
 	for input, expected := range testCases {
 		t.Run(fmt.Sprintf("%d players", input), func(t *testing.T) {
 			g := Game{
-				playerCount: input,
+				players: make([]Player, input),
 			}
 			if actual := g.PlayerCount(); actual != expected {
 				t.Fatalf("expected %d but got %d", expected, actual)
@@ -52,15 +54,13 @@ func TestGamePlayersDirect(t *testing.T) {
 	for i, inputs := range playerTestValues[1:] {
 		count := i + 1
 		t.Run(fmt.Sprintf("%d players", count), func(t *testing.T) {
-			g := Game{
-				playerCount: count,
-			}
+			g := Game{}
 			for name, color := range inputs {
 				player := Player{
 					Name:  name,
 					color: color,
 				}
-				g.players[i] = player
+				g.players = append(g.players, player)
 			}
 			players := g.Players()
 			expected, actual := len(inputs), len(players)
@@ -142,8 +142,8 @@ func TestGameAddPlayerValid(t *testing.T) {
 					t.Fatalf("failed to add player: %s", err)
 				}
 				j++
-				if g.playerCount != j {
-					t.Fatalf("expected player count %d but got %d", j, g.playerCount)
+				if g.PlayerCount() != j {
+					t.Fatalf("expected player count %d but got %d", j, g.PlayerCount())
 				}
 			}
 
@@ -246,56 +246,60 @@ func TestGameRemovePlayerValid(t *testing.T) {
 			for name, color := range inputs {
 				g, _ = g.AddPlayer(name, color)
 			}
-			for name := range inputs {
-				if g, err = g.RemovePlayer(name); err != nil {
-					t.Fatalf("failed to remove player %s", name)
+			for _, player := range g.Players() {
+				if g, err = g.RemovePlayer(player.ID()); err != nil {
+					t.Fatalf("failed to remove player %s", player.Name)
 				}
 				count--
 				if actual := g.PlayerCount(); actual != count {
-					t.Fatalf("expected %d players after removing %s but got %d", count, name, actual)
+					t.Fatalf("expected %d players after removing %s but got %d", count, player.Name, actual)
+				}
+				if _, found := g.Player(player.ID()); found {
+					t.Fatalf("player %s is still in the game after removal", player.Name)
 				}
 			}
 		})
 	}
 }
 
+func TestGameRemovePlayerKeepsOrder(t *testing.T) {
+	var (
+		err   error
+		g     = Game{}
+		names = []string{"Sergio", "Traci", "Erik", "Emmett"}
+	)
+
+	for _, name := range names {
+		if g, err = g.AddPlayer(name, playerTestValues[4][name]); err != nil {
+			t.Fatalf("failed to add player %s: %s", name, err)
+		}
+	}
+	if g, err = g.RemovePlayer(g.Players()[1].ID()); err != nil {
+		t.Fatalf("failed to remove player: %s", err)
+	}
+	for i, name := range []string{"Sergio", "Erik", "Emmett"} {
+		if actual := g.Players()[i].Name; actual != name {
+			t.Fatalf("expected %s in seat %d but got %s", name, i, actual)
+		}
+	}
+}
+
 func TestGameRemovePlayerNoPlayers(t *testing.T) {
 	g := Game{}
-	if _, err := g.RemovePlayer("Myra"); err == nil {
+	if _, err := g.RemovePlayer(1); err == nil {
 		t.Fatal("shouldn't be able to remove a player with no players")
 	}
 }
 
-func TestGameRemovePlayerEmptyName(t *testing.T) {
+func TestGameRemovePlayerZeroID(t *testing.T) {
 	g := Game{}
 	g, _ = g.AddPlayer("Fannie", Blue)
-	if _, err := g.RemovePlayer(""); err == nil {
-		t.Fatal("shouldn't be able to remove player with empty name")
+	if _, err := g.RemovePlayer(0); err == nil {
+		t.Fatal("shouldn't be able to remove player with the zero ID")
 	}
 }
 
-func TestGameRemovePlayerInvalidNames(t *testing.T) {
-	testCases := [][]string{
-		{
-			"Grace",
-		},
-		{
-			"Marvin",
-			"Bobby",
-		},
-		{
-			"Kayla",
-			"Sally",
-			"Ashley",
-		},
-		{
-			"Keith",
-			"Christie",
-			"Lorenzo",
-			"Rene",
-		},
-	}
-
+func TestGameRemovePlayerInvalidIDs(t *testing.T) {
 	for i, inputs := range playerTestValues[1:] {
 		t.Run(fmt.Sprintf("%d players", i+1), func(t *testing.T) {
 			var (
@@ -306,15 +310,54 @@ func TestGameRemovePlayerInvalidNames(t *testing.T) {
 			for name, color := range inputs {
 				g, _ = g.AddPlayer(name, color)
 			}
-			for _, name := range testCases[i] {
-				if g, err = g.RemovePlayer(name); err == nil {
-					t.Fatalf("shouldn't be able to remove non-existant player %s", name)
+			for id := PlayerID(len(inputs) + 1); id <= PlayerID(2*len(inputs)); id++ {
+				if g, err = g.RemovePlayer(id); err == nil {
+					t.Fatalf("shouldn't be able to remove non-existant %s", id)
 				}
 			}
 		})
 	}
 }
 
+func TestGameAddPlayerDuplicateName(t *testing.T) {
+	testCases := map[string]string{
+		"exact":      "Rosa",
+		"mixed case": "rOSA",
+		"lower case": "rosa",
+		"upper case": "ROSA",
+	}
+
+	for name, duplicate := range testCases {
+		t.Run(name, func(t *testing.T) {
+			g := Game{}
+			g, _ = g.AddPlayer("Rosa", Blue)
+			if _, err := g.AddPlayer(duplicate, Green); err == nil {
+				t.Fatalf("shouldn't be able to add a second player named %s", duplicate)
+			}
+		})
+	}
+}
+
+func TestGamePlayerIDsStable(t *testing.T) {
+	g := Game{}
+	g, _ = g.AddPlayer("Gabriel", Green)
+	g, _ = g.AddPlayer("Lori", Blue)
+	lori := g.Players()[1]
+
+	g, _ = g.RemovePlayer(g.Players()[0].ID())
+	g, _ = g.AddPlayer("Clinton", Yellow)
+
+	switch players := g.Players(); {
+	case players[0].ID() != lori.ID():
+		t.Fatalf("expected Lori to keep %s but got %s", lori.ID(), players[0].ID())
+	case players[1].ID() == lori.ID() || players[1].ID() == 1:
+		t.Fatalf("new player reused %s", players[1].ID())
+	}
+	if found, ok := g.Player(lori.ID()); !ok || found.Name != "Lori" {
+		t.Fatalf("failed to look up Lori by %s", lori.ID())
+	}
+}
+
 func TestTakeTurn(t *testing.T) {
 	var (
 		testCases = map[string]Color{
@@ -344,11 +387,11 @@ func TestTakeTurn(t *testing.T) {
 			"Debora Packard":     Yellow,
 			"Taisha Locklear":    Green,
 		}
-		validSpins = make(map[int]bool)
+		validFaces = make(map[FaceID]bool)
 	)
 
-	for _, val := range spinnerValues {
-		validSpins[val] = true
+	for _, face := range standardFaces {
+		validFaces[face.ID] = true
 	}
 
 	for name, color := range testCases {
@@ -362,17 +405,17 @@ func TestTakeTurn(t *testing.T) {
 				turn Turn
 			)
 
-			switch turn, player, err = takeTurn(player); {
+			switch turn, player, err = takeTurn(player, StandardRules(), CryptoSpinner{}); {
 			case err != nil:
 				t.Fatal("failed to generate a random number; local entropy is likely low")
-			case !validSpins[turn.Spin]:
-				t.Fatalf("turn has an invalid spin value: %d", turn.Spin)
+			case !validFaces[turn.Face.ID]:
+				t.Fatalf("turn has an invalid face: %s", turn.Face.ID)
 			case turn.Player.Name != player.Name:
 				t.Fatalf("player name should not change from %s to %s", player.Name, turn.Player.Name)
-			case turn.Spin >= 0 && turn.Player.cherries != turn.Spin:
-				t.Fatalf("expected %d cherries after turn but got %d", turn.Spin, turn.Player.cherries)
-			case turn.Spin < 0 && turn.Player.cherries != 0:
-				t.Fatalf("player should not have less than 0 cherries; got %d after spin %d", turn.Player.cherries, turn.Spin)
+			case turn.Face.Effect >= 0 && !turn.Face.Spill && turn.Player.cherries != turn.Face.Effect:
+				t.Fatalf("expected %d cherries after turn but got %d", turn.Face.Effect, turn.Player.cherries)
+			case (turn.Face.Effect < 0 || turn.Face.Spill) && turn.Player.cherries != 0:
+				t.Fatalf("player should not have less than 0 cherries; got %d after spinning %s", turn.Player.cherries, turn.Face)
 			}
 		})
 	}
@@ -401,14 +444,14 @@ func TestGamePlayValid(t *testing.T) {
 					t.Fatalf("failed to add player %s with color %s", name, color)
 				}
 			}
-			if g.playerCount != len(inputs) {
-				t.Fatalf("expected player count %d but got %d", len(inputs), g.playerCount)
+			if g.PlayerCount() != len(inputs) {
+				t.Fatalf("expected player count %d but got %d", len(inputs), g.PlayerCount())
 			}
 
 			switch turns, winner, err = g.Play(); {
 			case turns == nil:
 				t.Fatal("turn list is empty")
-			case len(turns) < 2*g.playerCount+1:
+			case len(turns) < 2*g.PlayerCount()+1:
 				t.Fatalf("too few turns to have a winner; got %d turns", len(turns))
 			case winner.cherries != 10:
 				t.Fatalf("expected winner to have 10 cherries but got %d", winner.cherries)
@@ -418,3 +461,492 @@ func TestGamePlayValid(t *testing.T) {
 		})
 	}
 }
+
+func TestGamePlaySeeded(t *testing.T) {
+	for _, inputs := range playerTestValues[1:] {
+		t.Run(fmt.Sprintf("%d players", len(inputs)), func(t *testing.T) {
+			g := Game{}
+			for _, name := range []string{"Sergio", "Traci", "Erik", "Emmett"}[:len(inputs)] {
+				var err error
+				if g, err = g.AddPlayer(name, playerTestValues[4][name]); err != nil {
+					t.Fatalf("failed to add player %s: %s", name, err)
+				}
+			}
+
+			for seed := int64(0); seed < 20; seed++ {
+				turnsA, winnerA, errA := g.WithSpinner(NewSeededSpinner(seed)).Play()
+				turnsB, winnerB, errB := g.WithSpinner(NewSeededSpinner(seed)).Play()
+				switch {
+				case errA != nil || errB != nil:
+					t.Fatalf("unexpected error with seed %d: %v, %v", seed, errA, errB)
+				case !reflect.DeepEqual(turnsA, turnsB):
+					t.Fatalf("seed %d produced different turns", seed)
+				case winnerA != winnerB:
+					t.Fatalf("seed %d produced different winners: %s and %s", seed, winnerA, winnerB)
+				}
+			}
+		})
+	}
+}
+
+type fixedSpinner []int
+
+func (f *fixedSpinner) Spin(n int) (int, error) {
+	spin := (*f)[0]
+	*f = (*f)[1:]
+	return spin, nil
+}
+
+func TestGamePlayFixedSpins(t *testing.T) {
+	g := Game{}
+	g, _ = g.AddPlayer("Lori", Blue)
+	g, _ = g.AddPlayer("Clinton", Yellow)
+
+	// spinner faces are 1, 2, 3, 4, -2, -2, -10
+	spins := fixedSpinner{3, 0, 3, 6, 1}
+	turns, winner, err := g.WithSpinner(&spins).Play()
+	switch {
+	case err != nil:
+		t.Fatalf("unexpected error: %s", err)
+	case len(turns) != 5:
+		t.Fatalf("expected 5 turns but got %d", len(turns))
+	case winner.Name != "Lori":
+		t.Fatalf("expected Lori to win but got %s", winner.Name)
+	case turns[3].Face.ID != BucketID || turns[3].Player.cherries != 0:
+		t.Fatalf("expected Clinton to spill the bucket on turn 4; got %s with %d cherries", turns[3].Face, turns[3].Player.cherries)
+	}
+}
+
+func TestGameNextTurn(t *testing.T) {
+	g := Game{}
+	g, _ = g.AddPlayer("Lori", Blue)
+	g, _ = g.AddPlayer("Clinton", Yellow)
+	if g.Phase() != Setup {
+		t.Fatalf("expected phase %s but got %s", Setup, g.Phase())
+	}
+
+	// spinner faces are 1, 2, 3, 4, -2, -2, -10
+	spins := fixedSpinner{3, 0, 3, 6, 1}
+	g = g.WithSpinner(&spins)
+	expectedSeats := []string{"Lori", "Clinton", "Lori", "Clinton", "Lori"}
+	for i, name := range expectedSeats {
+		current, ok := g.CurrentPlayer()
+		if !ok || current.Name != name {
+			t.Fatalf("expected %s to spin turn %d but got %s", name, i+1, current.Name)
+		}
+
+		var (
+			err  error
+			over bool
+			turn Turn
+		)
+		if g, turn, over, err = g.NextTurn(); err != nil {
+			t.Fatalf("unexpected error on turn %d: %s", i+1, err)
+		}
+		if turn.Player.Name != name {
+			t.Fatalf("expected turn %d to belong to %s but got %s", i+1, name, turn.Player.Name)
+		}
+		if last := i == len(expectedSeats)-1; over != last {
+			t.Fatalf("expected game over to be %t after turn %d", last, i+1)
+		}
+		if len(g.Turns()) != i+1 {
+			t.Fatalf("expected %d recorded turns but got %d", i+1, len(g.Turns()))
+		}
+	}
+
+	if g.Phase() != Finished {
+		t.Fatalf("expected phase %s but got %s", Finished, g.Phase())
+	}
+	if winner, ok := g.Winner(); !ok || winner.Name != "Lori" {
+		t.Fatalf("expected Lori to win but got %s", winner.Name)
+	}
+	if _, _, _, err := g.NextTurn(); err == nil {
+		t.Fatal("shouldn't be able to take a turn after the game is over")
+	}
+}
+
+func TestGameNextTurnNoPlayers(t *testing.T) {
+	if _, _, _, err := (Game{}).NextTurn(); err == nil {
+		t.Fatal("shouldn't be able to take a turn without any players")
+	}
+}
+
+func TestGameNextTurnLocksRoster(t *testing.T) {
+	g := Game{}
+	g, _ = g.AddPlayer("Lori", Blue)
+	g, _ = g.AddPlayer("Clinton", Yellow)
+
+	spins := fixedSpinner{0}
+	g, _, _, _ = g.WithSpinner(&spins).NextTurn()
+	if g.Phase() != InProgress {
+		t.Fatalf("expected phase %s but got %s", InProgress, g.Phase())
+	}
+	if _, err := g.AddPlayer("Gabriel", Green); err == nil {
+		t.Fatal("shouldn't be able to add a player once the game has started")
+	}
+	if _, err := g.RemovePlayer(g.Players()[0].ID()); err == nil {
+		t.Fatal("shouldn't be able to remove a player once the game has started")
+	}
+
+	g = g.Reset()
+	switch {
+	case g.Phase() != Setup:
+		t.Fatalf("expected phase %s after reset but got %s", Setup, g.Phase())
+	case len(g.Turns()) != 0:
+		t.Fatalf("expected no turns after reset but got %d", len(g.Turns()))
+	case g.Players()[0].cherries != 0:
+		t.Fatalf("expected no cherries after reset but got %d", g.Players()[0].cherries)
+	}
+	if _, err := g.AddPlayer("Gabriel", Green); err != nil {
+		t.Fatalf("failed to add a player after reset: %s", err)
+	}
+}
+
+func TestGameApplyFace(t *testing.T) {
+	g := Game{}
+	g, _ = g.AddPlayer("Lori", Blue)
+	g, _ = g.AddPlayer("Clinton", Yellow)
+
+	// the spinner must not be used when the face is already known
+	g = g.WithSpinner(&fixedSpinner{})
+	faces := []Face{PickFace(4), PickFace(1), PickFace(4), BucketFace, {ID: "pick-2"}}
+	expectedCherries := []int{4, 1, 8, 0, 10}
+	for i, face := range faces {
+		var (
+			err  error
+			over bool
+			turn Turn
+		)
+		if g, turn, over, err = g.ApplyFace(face); err != nil {
+			t.Fatalf("unexpected error on turn %d: %s", i+1, err)
+		}
+		if turn.Player.cherries != expectedCherries[i] {
+			t.Fatalf("expected %d cherries after turn %d but got %d", expectedCherries[i], i+1, turn.Player.cherries)
+		}
+		if last := i == len(faces)-1; over != last {
+			t.Fatalf("expected game over to be %t after turn %d", last, i+1)
+		}
+	}
+
+	// faces are looked up by ID, so the ruleset's version of the face is recorded
+	if face := g.Turns()[4].Face; face != PickFace(2) {
+		t.Fatalf("expected the ruleset's face to be recorded but got %+v", face)
+	}
+	if winner, ok := g.Winner(); !ok || winner.Name != "Lori" {
+		t.Fatalf("expected Lori to win but got %s", winner.Name)
+	}
+	if _, _, _, err := g.ApplyFace(PickFace(1)); err == nil {
+		t.Fatal("shouldn't be able to take a turn after the game is over")
+	}
+}
+
+func TestGameApplyFaceUnknown(t *testing.T) {
+	g := Game{}
+	g, _ = g.AddPlayer("Lori", Blue)
+
+	if _, _, _, err := g.ApplyFace(PickFace(5)); err == nil {
+		t.Fatal("shouldn't be able to apply a face that isn't on the spinner")
+	}
+	if _, _, _, err := (Game{}).ApplyFace(PickFace(1)); err == nil {
+		t.Fatal("shouldn't be able to take a turn without any players")
+	}
+}
+
+func TestGamePlayLeavesOriginalUntouched(t *testing.T) {
+	g := Game{}
+	g, _ = g.AddPlayer("Lori", Blue)
+
+	if _, _, err := g.WithSpinner(NewSeededSpinner(3)).Play(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if g.Phase() != Setup || len(g.Turns()) != 0 {
+		t.Fatalf("playing a copy shouldn't change the original; got phase %s with %d turns", g.Phase(), len(g.Turns()))
+	}
+}
+
+func TestGameClassroomRoster(t *testing.T) {
+	rules := StandardRules()
+	rules.MaxPlayers = 12
+	rules.ShareColors = true
+	g, err := New(rules)
+	if err != nil {
+		t.Fatalf("failed to create game: %s", err)
+	}
+
+	for i := 0; i < 12; i++ {
+		name, color := fmt.Sprintf("Kid %d", i+1), Colors()[i%len(Colors())]
+		if g, err = g.AddPlayer(name, color); err != nil {
+			t.Fatalf("failed to add %s with color %s: %s", name, color, err)
+		}
+	}
+	if _, err = g.AddPlayer("Kid 13", Blue); err == nil {
+		t.Fatal("shouldn't be able to add more players than the ruleset allows")
+	}
+	for color, available := range g.AvailableColors() {
+		if !available {
+			t.Fatalf("color %s should still be available when colors are shared", color)
+		}
+	}
+
+	turns, winner, err := g.WithSpinner(NewSeededSpinner(12)).Play()
+	switch {
+	case err != nil:
+		t.Fatalf("unexpected error: %s", err)
+	case len(turns) < 12:
+		t.Fatalf("expected every kid to spin at least once but got %d turns", len(turns))
+	case winner.cherries != WinningScore:
+		t.Fatalf("expected winner to have %d cherries but got %d", WinningScore, winner.cherries)
+	}
+	for _, player := range g.Players() {
+		if player.cherries != 0 {
+			t.Fatalf("playing a copy shouldn't change the original roster; %s has %d cherries", player.Name, player.cherries)
+		}
+	}
+}
+
+func TestGameAdditionalColors(t *testing.T) {
+	rules := StandardRules()
+	rules.MaxPlayers = len(Colors())
+	g, _ := New(rules)
+
+	var err error
+	for i, color := range Colors() {
+		if g, err = g.AddPlayer(fmt.Sprintf("Player %d", i+1), color); err != nil {
+			t.Fatalf("failed to add a player with color %s: %s", color, err)
+		}
+	}
+	rules.MaxPlayers++
+	g.rules = rules
+	if _, err = g.AddPlayer("Player 9", Blue); err == nil {
+		t.Fatal("shouldn't be able to reuse a color unless the ruleset allows sharing")
+	}
+}
+
+func TestGameUpdatePlayerValid(t *testing.T) {
+	testCases := map[string]struct {
+		name  string
+		color Color
+	}{
+		"rename":      {"Noah", Blue},
+		"recolor":     {"Rosa", Yellow},
+		"both":        {"Noah", Yellow},
+		"unchanged":   {"Rosa", Blue},
+		"case change": {"ROSA", Blue},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			var (
+				err error
+				g   = Game{}
+			)
+
+			g, _ = g.AddPlayer("Marcus", Green)
+			g, _ = g.AddPlayer("Rosa", Blue)
+			g, _ = g.AddPlayer("Terry", Red)
+			id := g.Players()[1].ID()
+			if g, err = g.UpdatePlayer(id, tc.name, tc.color); err != nil {
+				t.Fatalf("failed to update player: %s", err)
+			}
+
+			player := g.Players()[1]
+			if player.ID() != id || player.Name != tc.name || player.Color() != tc.color {
+				t.Fatalf("expected %s %s in seat 1 but got %s %s", tc.color, tc.name, player.Color(), player.Name)
+			}
+			if actual := g.PlayerCount(); actual != 3 {
+				t.Fatalf("expected 3 players but got %d", actual)
+			}
+		})
+	}
+}
+
+func TestGameUpdatePlayerInvalid(t *testing.T) {
+	testCases := map[string]struct {
+		name  string
+		color Color
+	}{
+		"empty name":     {"", Blue},
+		"taken name":     {"Marcus", Blue},
+		"taken any case": {"mARCUS", Blue},
+		"taken color":    {"Rosa", Green},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			g := Game{}
+			g, _ = g.AddPlayer("Marcus", Green)
+			g, _ = g.AddPlayer("Rosa", Blue)
+			if _, err := g.UpdatePlayer(g.Players()[1].ID(), tc.name, tc.color); err == nil {
+				t.Fatalf("shouldn't be able to update Rosa to %s %s", tc.color, tc.name)
+			}
+		})
+	}
+}
+
+func TestGameUpdatePlayerInvalidID(t *testing.T) {
+	g := Game{}
+	g, _ = g.AddPlayer("Fannie", Blue)
+	for _, id := range []PlayerID{0, 2} {
+		if _, err := g.UpdatePlayer(id, "Peggy", Red); err == nil {
+			t.Fatalf("shouldn't be able to update non-existant %s", id)
+		}
+	}
+}
+
+func TestGameUpdatePlayerLeavesOriginal(t *testing.T) {
+	g := Game{}
+	g, _ = g.AddPlayer("Josh", Yellow)
+	if _, err := g.UpdatePlayer(g.Players()[0].ID(), "Courtney", Red); err != nil {
+		t.Fatalf("failed to update player: %s", err)
+	}
+	if player := g.Players()[0]; player.Name != "Josh" || player.Color() != Yellow {
+		t.Fatalf("original game changed to %s %s", player.Color(), player.Name)
+	}
+}
+
+func seatNames(g Game) []string {
+	var names []string
+	for _, player := range g.Players() {
+		names = append(names, player.Name)
+	}
+	return names
+}
+
+func TestGameMovePlayer(t *testing.T) {
+	testCases := []struct {
+		seat     int
+		offset   int
+		expected []string
+	}{
+		{0, 0, []string{"Sergio", "Traci", "Erik", "Emmett"}},
+		{0, 1, []string{"Traci", "Sergio", "Erik", "Emmett"}},
+		{0, 3, []string{"Traci", "Erik", "Emmett", "Sergio"}},
+		{2, -1, []string{"Sergio", "Erik", "Traci", "Emmett"}},
+		{3, -3, []string{"Emmett", "Sergio", "Traci", "Erik"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("seat %d by %d", tc.seat, tc.offset), func(t *testing.T) {
+			var (
+				err error
+				g   = Game{}
+			)
+
+			for _, name := range []string{"Sergio", "Traci", "Erik", "Emmett"} {
+				g, _ = g.AddPlayer(name, playerTestValues[4][name])
+			}
+			if g, err = g.MovePlayer(g.Players()[tc.seat].ID(), tc.offset); err != nil {
+				t.Fatalf("failed to move player: %s", err)
+			}
+			if actual := seatNames(g); !reflect.DeepEqual(actual, tc.expected) {
+				t.Fatalf("expected seats %v but got %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestGameMovePlayerInvalid(t *testing.T) {
+	g := Game{}
+	g, _ = g.AddPlayer("Rosa", Blue)
+	g, _ = g.AddPlayer("Noah", Green)
+
+	testCases := map[string]struct {
+		id     PlayerID
+		offset int
+	}{
+		"before first": {g.Players()[0].ID(), -1},
+		"after last":   {g.Players()[1].ID(), 1},
+		"unknown ID":   {3, 0},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := g.MovePlayer(tc.id, tc.offset); err == nil {
+				t.Fatalf("shouldn't be able to move %s by %d", tc.id, tc.offset)
+			}
+		})
+	}
+}
+
+func TestGameShufflePlayers(t *testing.T) {
+	var (
+		err error
+		g   = Game{}
+	)
+
+	for _, name := range []string{"Sergio", "Traci", "Erik", "Emmett"} {
+		g, _ = g.AddPlayer(name, playerTestValues[4][name])
+	}
+	spins := fixedSpinner{0, 1, 0}
+	if g, err = g.WithSpinner(&spins).ShufflePlayers(); err != nil {
+		t.Fatalf("failed to shuffle players: %s", err)
+	}
+
+	expected := []string{"Erik", "Emmett", "Traci", "Sergio"}
+	if actual := seatNames(g); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected seats %v but got %v", expected, actual)
+	}
+
+	// drawing the order they're already in changes nothing
+	logged := len(g.Log())
+	spins = fixedSpinner{3, 2, 1}
+	if g, err = g.WithSpinner(&spins).ShufflePlayers(); err != nil {
+		t.Fatalf("failed to shuffle players: %s", err)
+	}
+	if actual := seatNames(g); !reflect.DeepEqual(actual, expected) || len(g.Log()) != logged {
+		t.Fatalf("expected seats %v and nothing logged but got %v and %v", expected, actual, entryNames(g.Log()[logged:]))
+	}
+}
+
+func TestGameSeatYoungestFirst(t *testing.T) {
+	var (
+		err  error
+		g    = Game{}
+		ages = map[string]int{"Sergio": 9, "Traci": 0, "Erik": 6, "Emmett": 9}
+	)
+
+	for _, name := range []string{"Sergio", "Traci", "Erik", "Emmett"} {
+		g, _ = g.AddPlayer(name, playerTestValues[4][name])
+		players := g.Players()
+		if g, err = g.SetPlayerAge(players[len(players)-1].ID(), ages[name]); err != nil {
+			t.Fatalf("failed to set the age of %s: %s", name, err)
+		}
+	}
+	if g, err = g.SeatYoungestFirst(); err != nil {
+		t.Fatalf("failed to seat players by age: %s", err)
+	}
+
+	// Traci's age isn't known, and Sergio was seated before Emmett
+	expected := []string{"Erik", "Sergio", "Emmett", "Traci"}
+	if actual := seatNames(g); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected seats %v but got %v", expected, actual)
+	}
+	if age := g.Players()[0].Age(); age != 6 {
+		t.Fatalf("expected Erik to be 6 but got %d", age)
+	}
+
+	// seating them again changes nothing
+	logged := len(g.Log())
+	if g, err = g.SeatYoungestFirst(); err != nil || len(g.Log()) != logged {
+		t.Fatalf("expected nothing logged when already seated by age but got %v (%v)", entryNames(g.Log()[logged:]), err)
+	}
+}
+
+func TestGameSeatYoungestFirstNoAges(t *testing.T) {
+	g := Game{}
+	g, _ = g.AddPlayer("Rosa", Blue)
+	if _, err := g.SeatYoungestFirst(); err == nil {
+		t.Fatal("shouldn't be able to seat players by age without any ages")
+	}
+}
+
+func TestGameSetPlayerAgeInvalid(t *testing.T) {
+	g := Game{}
+	g, _ = g.AddPlayer("Rosa", Blue)
+	if _, err := g.SetPlayerAge(g.Players()[0].ID(), -1); err == nil {
+		t.Fatal("shouldn't be able to set a negative age")
+	}
+	if _, err := g.SetPlayerAge(2, 8); err == nil {
+		t.Fatal("shouldn't be able to set the age of a non-existant player")
+	}
+}
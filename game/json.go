@@ -0,0 +1,393 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FormatVersion is the version of the JSON format written by Game.MarshalJSON.
+const FormatVersion = 1
+
+func (c Color) MarshalText() ([]byte, error) {
+	if _, err := ParseColor(c.String()); err != nil {
+		return nil, fmt.Errorf("can't encode invalid color %d", int(c))
+	}
+	return []byte(c.String()), nil
+}
+
+func (c *Color) UnmarshalText(text []byte) error {
+	parsed, err := ParseColor(string(text))
+	if err != nil {
+		return err
+	}
+
+	*c = parsed
+	return nil
+}
+
+// ParseColor returns the Color named s, ignoring case.
+func ParseColor(s string) (Color, error) {
+	for _, color := range colors {
+		if strings.EqualFold(s, color.String()) {
+			return color, nil
+		}
+	}
+
+	return InvalidColor, fmt.Errorf("unknown color %q", s)
+}
+
+func (o Overflow) MarshalText() ([]byte, error) {
+	if o < Clamp || o > Overshoot {
+		return nil, fmt.Errorf("can't encode unknown overflow policy %d", int(o))
+	}
+	return []byte(o.String()), nil
+}
+
+func (o *Overflow) UnmarshalText(text []byte) error {
+	parsed, err := ParseOverflow(string(text))
+	if err != nil {
+		return err
+	}
+
+	*o = parsed
+	return nil
+}
+
+func (p Phase) MarshalText() ([]byte, error) {
+	if p < Setup || p > Finished {
+		return nil, fmt.Errorf("can't encode unknown phase %d", int(p))
+	}
+	return []byte(p.String()), nil
+}
+
+func (p *Phase) UnmarshalText(text []byte) error {
+	for _, phase := range []Phase{Setup, InProgress, Finished} {
+		if string(text) == phase.String() {
+			*p = phase
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown phase %q", text)
+}
+
+type playerJSON struct {
+	ID       PlayerID `json:"id"`
+	Name     string   `json:"name"`
+	Color    Color    `json:"color"`
+	Cherries int      `json:"cherries"`
+	Age      int      `json:"age,omitempty"`
+}
+
+func (p Player) MarshalJSON() ([]byte, error) {
+	return json.Marshal(playerJSON{
+		ID:       p.id,
+		Name:     p.Name,
+		Color:    p.color,
+		Cherries: p.cherries,
+		Age:      p.age,
+	})
+}
+
+// UnmarshalJSON decodes a single player; it is checked against the rest of the roster when decoded as part of a Game.
+func (p *Player) UnmarshalJSON(data []byte) error {
+	var decoded playerJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*p = Player{
+		Name:     decoded.Name,
+		age:      decoded.Age,
+		cherries: decoded.Cherries,
+		color:    decoded.Color,
+		id:       decoded.ID,
+	}
+	return nil
+}
+
+type turnJSON struct {
+	Face   Face   `json:"face"`
+	Player Player `json:"player"`
+}
+
+func (t Turn) MarshalJSON() ([]byte, error) {
+	return json.Marshal(turnJSON(t))
+}
+
+func (t *Turn) UnmarshalJSON(data []byte) error {
+	var decoded turnJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*t = Turn(decoded)
+	return nil
+}
+
+// loggedEvents names the events kept in a Game's log, as their entries are written.
+var loggedEvents = map[string]Event{
+	"playerAdded":   PlayerAdded{},
+	"playerRemoved": PlayerRemoved{},
+	"playerUpdated": PlayerUpdated{},
+	"seatsChanged":  SeatsChanged{},
+	"turnTaken":     TurnTaken{},
+	"gameWon":       GameWon{},
+	"gameReset":     GameReset{},
+}
+
+type entryJSON struct {
+	Seq   int             `json:"seq"`
+	Time  time.Time       `json:"time"`
+	Type  string          `json:"type"`
+	Event json.RawMessage `json:"event"`
+}
+
+func (e Entry) MarshalJSON() ([]byte, error) {
+	for name, logged := range loggedEvents {
+		if reflect.TypeOf(e.Event) != reflect.TypeOf(logged) {
+			continue
+		}
+
+		event, err := json.Marshal(e.Event)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(entryJSON{
+			Seq:   e.Seq,
+			Time:  e.Time,
+			Type:  name,
+			Event: event,
+		})
+	}
+
+	return nil, fmt.Errorf("can't encode entry %d; %T events aren't logged", e.Seq, e.Event)
+}
+
+// UnmarshalJSON decodes a single entry; it is checked against the entries before it when decoded as part of a Game.
+func (e *Entry) UnmarshalJSON(data []byte) error {
+	var decoded entryJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	logged, ok := loggedEvents[decoded.Type]
+	if !ok {
+		return fmt.Errorf("entry %d has unknown type %q", decoded.Seq, decoded.Type)
+	}
+	event := reflect.New(reflect.TypeOf(logged))
+	if err := json.Unmarshal(decoded.Event, event.Interface()); err != nil {
+		return fmt.Errorf("entry %d: %s", decoded.Seq, err)
+	}
+
+	*e = Entry{
+		Seq:   decoded.Seq,
+		Time:  decoded.Time,
+		Event: event.Elem().Interface().(Event),
+	}
+	return nil
+}
+
+type gameJSON struct {
+	Version int      `json:"version"`
+	Rules   Ruleset  `json:"rules"`
+	Phase   Phase    `json:"phase"`
+	Players []Player `json:"players"`
+	// Current is the seat of the player due to spin next, or of the winner once finished.
+	Current int      `json:"current"`
+	LastID  PlayerID `json:"lastId"`
+	Leader  PlayerID `json:"leader,omitempty"`
+	Turns   []Turn   `json:"turns"`
+	// Log is missing from games saved before logs were kept.
+	Log []Entry `json:"log,omitempty"`
+}
+
+/*
+MarshalJSON encodes everything needed to carry on with g later, including its rules, turns, and log so far.
+The spinner, clock, and subscribers are not saved.
+*/
+func (g Game) MarshalJSON() ([]byte, error) {
+	encoded := gameJSON{
+		Version: FormatVersion,
+		Rules:   g.activeRules(),
+		Phase:   g.phase,
+		Players: g.players,
+		Current: g.current,
+		LastID:  g.lastID,
+		Leader:  g.leader,
+		Turns:   g.turns,
+		Log:     g.log,
+	}
+	if encoded.Players == nil {
+		encoded.Players = []Player{}
+	}
+	if encoded.Turns == nil {
+		encoded.Turns = []Turn{}
+	}
+
+	return json.Marshal(encoded)
+}
+
+/*
+UnmarshalJSON decodes a Game written by MarshalJSON.
+The roster is rebuilt through AddPlayer so that it obeys the same rules as a game set up by hand,
+and the recorded progress is checked for consistency with the roster and rules.
+The log must add up to the rest of the saved game; a game saved without a log is given one made up from its roster and
+turns, with no times.
+The decoded Game keeps the spinner, clock, and subscribers of g, if any.
+*/
+func (g *Game) UnmarshalJSON(data []byte) error {
+	var decoded gameJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	if decoded.Version != FormatVersion {
+		return fmt.Errorf("unsupported game format version %d; expected %d", decoded.Version, FormatVersion)
+	}
+
+	restored, err := New(decoded.Rules)
+	if err != nil {
+		return err
+	}
+	rules := restored.activeRules()
+
+	ids := make(map[PlayerID]bool)
+	for i, player := range decoded.Players {
+		if restored, err = restored.AddPlayer(player.Name, player.color); err != nil {
+			return fmt.Errorf("invalid player %d: %s", i+1, err)
+		}
+
+		switch {
+		case player.id <= 0 || player.id > decoded.LastID:
+			return fmt.Errorf("player %s has invalid ID %d", player.Name, int(player.id))
+		case ids[player.id]:
+			return fmt.Errorf("%s is used by more than one player", player.id)
+		case player.age < 0:
+			return fmt.Errorf("player %s can't be %d years old", player.Name, player.age)
+		case player.cherries < 0 || (player.cherries > rules.TargetScore && rules.Overflow != Overshoot):
+			return fmt.Errorf("player %s can't have %d cherries", player.Name, player.cherries)
+		case decoded.Phase == Setup && player.cherries != 0:
+			return fmt.Errorf("player %s can't have cherries before the game starts", player.Name)
+		case rules.Won(player.cherries) && (decoded.Phase != Finished || decoded.Current != i):
+			return fmt.Errorf("player %s has won but the game doesn't say so", player.Name)
+		}
+		ids[player.id] = true
+
+		restored.players[i].age = player.age
+		restored.players[i].id = player.id
+		restored.players[i].cherries = player.cherries
+	}
+
+	switch players := len(decoded.Players); {
+	case decoded.Phase != Setup && players == 0:
+		return fmt.Errorf("a game %s needs players", decoded.Phase)
+	case players > 0 && (decoded.Current < 0 || decoded.Current >= players):
+		return fmt.Errorf("current seat %d is outside of the roster", decoded.Current)
+	case players == 0 && decoded.Current != 0:
+		return fmt.Errorf("current seat %d is outside of the roster", decoded.Current)
+	case decoded.Phase == Setup && len(decoded.Turns) > 0:
+		return errors.New("a game that hasn't started can't have turns")
+	case decoded.Phase != Setup && len(decoded.Turns) == 0:
+		return fmt.Errorf("a game %s needs turns", decoded.Phase)
+	case decoded.Phase == Finished && !rules.Won(decoded.Players[decoded.Current].cherries):
+		return errors.New("the game is finished but nobody has won")
+	case decoded.Leader != 0 && !ids[decoded.Leader]:
+		return fmt.Errorf("leader %s is not a current player", decoded.Leader)
+	}
+	for i, turn := range decoded.Turns {
+		if !ids[turn.Player.id] {
+			return fmt.Errorf("turn %d belongs to %s, who is not a current player", i+1, turn.Player.id)
+		}
+	}
+
+	restored.current = decoded.Current
+	restored.lastID = decoded.LastID
+	restored.leader = decoded.Leader
+	restored.phase = decoded.Phase
+	if decoded.Phase == Finished {
+		restored.winner = decoded.Current
+	}
+	if len(decoded.Turns) > 0 {
+		restored.turns = decoded.Turns
+	}
+
+	log := decoded.Log
+	if log == nil {
+		log = madeUpLog(restored)
+	}
+	rebuilt, err := Game{rules: restored.rules}.replay(log)
+	if err != nil {
+		return fmt.Errorf("invalid log: %s", err)
+	}
+	if decoded.Log == nil {
+		// players removed before the game was saved left no trace of their IDs
+		rebuilt.lastID = restored.lastID
+	}
+	if !sameState(rebuilt, restored) {
+		return errors.New("the log doesn't add up to the saved game")
+	}
+	rebuilt.clock = g.clock
+	rebuilt.spinner = g.spinner
+	rebuilt.subscribers = g.subscribers
+
+	*g = rebuilt
+	return nil
+}
+
+/*
+madeUpLog returns a log for g, which was saved without one: its players join in order of ID and take their seats,
+then take its turns again.
+*/
+func madeUpLog(g Game) []Entry {
+	var (
+		events []Event
+		seated = g.clonePlayers()
+	)
+
+	for i := range seated {
+		seated[i].cherries = 0
+	}
+	joined := append([]Player(nil), seated...)
+	sort.Slice(joined, func(i, j int) bool {
+		return joined[i].id < joined[j].id
+	})
+	for _, player := range joined {
+		events = append(events, PlayerAdded{player})
+	}
+	if !reflect.DeepEqual(joined, seated) {
+		events = append(events, SeatsChanged{seated})
+	}
+	for i, turn := range g.turns {
+		events = append(events, TurnTaken{i + 1, turn})
+	}
+	if g.phase == Finished {
+		events = append(events, GameWon{g.players[g.winner], len(g.turns)})
+	}
+
+	log := make([]Entry, len(events))
+	for i, event := range events {
+		log[i] = Entry{Seq: i + 1, Event: event}
+	}
+	return log
+}
+
+// sameState reports whether a and b are at the same point of the same game, whatever their logs and how they're played.
+func sameState(a, b Game) bool {
+	a.log, b.log = nil, nil
+	a.clock, b.clock = nil, nil
+	a.spinner, b.spinner = nil, nil
+	a.subscribers, b.subscribers = nil, nil
+	if len(a.players) == 0 && len(b.players) == 0 {
+		a.players, b.players = nil, nil
+	}
+	if len(a.turns) == 0 && len(b.turns) == 0 {
+		a.turns, b.turns = nil, nil
+	}
+
+	return reflect.DeepEqual(a, b)
+}
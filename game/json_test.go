@@ -0,0 +1,208 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestColorText(t *testing.T) {
+	for color, name := range colorTestValues {
+		t.Run(name, func(t *testing.T) {
+			encoded, err := json.Marshal(color)
+			if err != nil {
+				t.Fatalf("failed to encode %s: %s", name, err)
+			}
+			if expected := fmt.Sprintf("%q", name); string(encoded) != expected {
+				t.Fatalf("expected %s but got %s", expected, encoded)
+			}
+
+			var decoded Color
+			if err = json.Unmarshal(encoded, &decoded); err != nil {
+				t.Fatalf("failed to decode %s: %s", encoded, err)
+			}
+			if decoded != color {
+				t.Fatalf("expected %s but got %s", color, decoded)
+			}
+		})
+	}
+}
+
+func TestColorTextInvalid(t *testing.T) {
+	if _, err := json.Marshal(InvalidColor); err == nil {
+		t.Fatal("shouldn't be able to encode an invalid color")
+	}
+
+	var c Color
+	if err := json.Unmarshal([]byte(`"chartreuse"`), &c); err == nil {
+		t.Fatal("shouldn't be able to decode an unknown color")
+	}
+}
+
+func roundTrip(t *testing.T, g Game) Game {
+	t.Helper()
+
+	encoded, err := json.Marshal(g)
+	if err != nil {
+		t.Fatalf("failed to encode game: %s", err)
+	}
+
+	var decoded Game
+	if err = json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("failed to decode game: %s\n%s", err, encoded)
+	}
+
+	return decoded
+}
+
+func TestGameJSONRoundTrip(t *testing.T) {
+	setup, _ := New(StandardRules())
+	setup, _ = setup.AddPlayer("Gabriel", Green)
+	setup, _ = setup.AddPlayer("Lori", Blue)
+	setup, _ = setup.AddPlayer("Clinton", Yellow)
+	setup, _ = setup.RemovePlayer(setup.Players()[0].ID())
+	setup, _ = setup.SetPlayerAge(setup.Players()[1].ID(), 7)
+
+	spins := fixedSpinner{3, 0, 3, 6, 1}
+	inProgress := setup.WithSpinner(&spins)
+	for i := 0; i < 3; i++ {
+		inProgress, _, _, _ = inProgress.NextTurn()
+	}
+	finished := inProgress
+	for finished.Phase() != Finished {
+		finished, _, _, _ = finished.NextTurn()
+	}
+
+	custom := StandardRules()
+	custom.TargetScore = 5
+	custom.Overflow = Overshoot
+	custom.MaxPlayers = 12
+	custom.ShareColors = true
+	overshoot, _ := New(custom)
+	overshoot, _ = overshoot.AddPlayer("Traci", Red)
+	overshoot, _ = overshoot.AddPlayer("Erik", Red)
+	overshoot = overshoot.WithSpinner(NewSeededSpinner(5))
+	for overshoot.Phase() != Finished {
+		overshoot, _, _, _ = overshoot.NextTurn()
+	}
+
+	testCases := map[string]Game{
+		"empty":       {rules: StandardRules()},
+		"setup":       setup,
+		"in progress": inProgress.WithSpinner(nil),
+		"finished":    finished.WithSpinner(nil),
+		"overshoot":   overshoot.WithSpinner(nil),
+	}
+
+	for name, g := range testCases {
+		t.Run(name, func(t *testing.T) {
+			decoded := roundTrip(t, g)
+			if !reflect.DeepEqual(decoded, g) {
+				t.Fatalf("game changed in a round trip\nbefore: %+v\nafter:  %+v", g, decoded)
+			}
+		})
+	}
+
+	// a restored game carries on exactly where it left off
+	spins = fixedSpinner{6, 1}
+	resumed := roundTrip(t, inProgress.WithSpinner(nil)).WithSpinner(&spins)
+	turns, winner, err := resumed.Play()
+	switch {
+	case err != nil:
+		t.Fatalf("failed to finish the restored game: %s", err)
+	case len(turns) != 5 || winner.Name != "Lori":
+		t.Fatalf("expected Lori to win in 5 turns but %s won in %d", winner.Name, len(turns))
+	}
+}
+
+func TestGameJSONInvalid(t *testing.T) {
+	valid := `{"version":1,"rules":{"targetScore":10,"faces":[{"id":"pick-1","name":"1 cherry","symbol":"","effect":1}],"overflow":"clamp","maxPlayers":2,"shareColors":false},` +
+		`"phase":"setup","players":[{"id":1,"name":"Lori","color":"blue","cherries":0},{"id":2,"name":"Clinton","color":"yellow","cherries":0}],"current":0,"lastId":2,"turns":[]}`
+
+	var g Game
+	if err := json.Unmarshal([]byte(valid), &g); err != nil {
+		t.Fatalf("failed to decode a valid game: %s", err)
+	}
+	if g.PlayerCount() != 2 || g.Rules().MaxPlayers != 2 {
+		t.Fatalf("decoded the wrong game: %+v", g)
+	}
+
+	testCases := map[string][2]string{
+		"version":               {`"version":1`, `"version":2`},
+		"duplicate name":        {`"name":"Clinton"`, `"name":"lori"`},
+		"shared color":          {`"color":"yellow"`, `"color":"blue"`},
+		"unknown color":         {`"color":"yellow"`, `"color":"teal"`},
+		"empty name":            {`"name":"Clinton"`, `"name":""`},
+		"duplicate ID":          {`"id":2`, `"id":1`},
+		"ID after lastId":       {`"lastId":2`, `"lastId":1`},
+		"too many players":      {`"maxPlayers":2`, `"maxPlayers":1`},
+		"invalid rules":         {`"targetScore":10`, `"targetScore":0`},
+		"unknown overflow":      {`"clamp"`, `"wrap"`},
+		"cherries early":        {`"cherries":0}]`, `"cherries":3}]`},
+		"started without turns": {`"phase":"setup"`, `"phase":"in progress"`},
+		"current seat":          {`"current":0`, `"current":5`},
+		"unknown phase":         {`"phase":"setup"`, `"phase":"paused"`},
+		"negative age":          {`"cherries":0}]`, `"cherries":0,"age":-4}]`},
+	}
+
+	for name, replacement := range testCases {
+		t.Run(name, func(t *testing.T) {
+			invalid := strings.Replace(valid, replacement[0], replacement[1], 1)
+			if invalid == valid {
+				t.Fatalf("test case doesn't change the input")
+			}
+			var g Game
+			if err := json.Unmarshal([]byte(invalid), &g); err == nil {
+				t.Fatalf("shouldn't be able to decode %s", invalid)
+			}
+		})
+	}
+}
+
+func TestGameJSONLog(t *testing.T) {
+	// a game saved before logs were kept is given one, after its players take their seats
+	old := `{"version":1,"rules":{"targetScore":10,"faces":[{"id":"pick-1","name":"1 cherry","symbol":"","effect":1}],"overflow":"clamp","maxPlayers":2,"shareColors":false},` +
+		`"phase":"in progress","players":[{"id":3,"name":"Lori","color":"blue","cherries":1},{"id":1,"name":"Clinton","color":"yellow","cherries":0}],"current":1,"lastId":3,"leader":3,` +
+		`"turns":[{"face":{"id":"pick-1","name":"1 cherry","symbol":"","effect":1},"player":{"id":3,"name":"Lori","color":"blue","cherries":1}}]}`
+
+	var g Game
+	if err := json.Unmarshal([]byte(old), &g); err != nil {
+		t.Fatalf("failed to decode a game without a log: %s", err)
+	}
+	expected := []string{"PlayerAdded", "PlayerAdded", "SeatsChanged", "TurnTaken"}
+	if actual := entryNames(g.Log()); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %v but got %v", expected, actual)
+	}
+	if !g.Log()[0].Time.IsZero() {
+		t.Fatalf("a made-up log shouldn't have times but got %s", g.Log()[0].Time)
+	}
+	if g.lastID != 3 {
+		t.Fatalf("expected the last ID given out to be kept but got %d", int(g.lastID))
+	}
+
+	encoded, err := json.Marshal(playedGame().WithSpinner(nil))
+	if err != nil {
+		t.Fatalf("failed to encode a game: %s", err)
+	}
+	testCases := map[string][2]string{
+		"lastId past log": {`"lastId":3`, `"lastId":4`},
+		"unknown entry":   {`"type":"gameWon"`, `"type":"gameLost"`},
+		"missing win":     {`"type":"gameWon"`, `"type":"gameReset"`},
+		"different face":  {`"seq":5,"time":"2022-08-14T09:34:00Z","type":"turnTaken","event":{"number":1,"turn":{"face":{"id":"pick-4"`, `"seq":5,"time":"2022-08-14T09:34:00Z","type":"turnTaken","event":{"number":1,"turn":{"face":{"id":"pick-3"`},
+	}
+
+	for name, replacement := range testCases {
+		t.Run(name, func(t *testing.T) {
+			invalid := strings.Replace(string(encoded), replacement[0], replacement[1], 1)
+			if invalid == string(encoded) {
+				t.Fatalf("test case doesn't change the input")
+			}
+			var g Game
+			if err := json.Unmarshal([]byte(invalid), &g); err == nil {
+				t.Fatalf("shouldn't be able to decode %s", invalid)
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+/*
+Entry is one event in a Game's log.
+A Game's state is whatever its log adds up to: every change to the roster, every turn, and every win is folded into the
+state as an Entry is appended, so any earlier state can be had again by replaying the log up to that point.
+CherriesLost, BucketSpilled, and LeadChanged are told to subscribers but not logged, since they follow from the turns.
+*/
+type Entry struct {
+	// Seq numbers the entries of a log from 1, in the order they happened.
+	Seq   int
+	Time  time.Time
+	Event Event
+}
+
+// WithClock returns a copy of g that stamps its log entries with the time from clock; a nil clock restores time.Now.
+func (g Game) WithClock(clock func() time.Time) Game {
+	g.clock = clock
+	return g
+}
+
+// now returns the time for a new log entry, in UTC and without a monotonic reading so that it survives encoding intact.
+func (g Game) now() time.Time {
+	clock := time.Now
+	if g.clock != nil {
+		clock = g.clock
+	}
+	return clock().UTC().Round(0)
+}
+
+// Log returns every entry in g's log, oldest first; the slice is a copy and may be modified freely.
+func (g Game) Log() []Entry {
+	return append([]Entry(nil), g.log...)
+}
+
+/*
+At returns g as it was just after the log entry numbered seq, by replaying g's log from the start; At(0) has no players.
+The past Game keeps the spinner and clock of g, but not its subscribers.
+*/
+func (g Game) At(seq int) (Game, error) {
+	if seq < 0 || seq > len(g.log) {
+		return g, fmt.Errorf("there is no entry %d in a log of %d entries", seq, len(g.log))
+	}
+
+	past := Game{rules: g.rules, spinner: g.spinner, clock: g.clock}
+	return past.replay(g.log[:seq])
+}
+
+/*
+Rebuild returns the game that log adds up to under rules, as At would.
+Every entry is checked against the state before it, so a log that couldn't have come from a game played by rules is an error.
+*/
+func Rebuild(rules Ruleset, log []Entry) (Game, error) {
+	g, err := New(rules)
+	if err != nil {
+		return g, err
+	}
+
+	return g.replay(log)
+}
+
+/*
+replay folds log into g, which must own its players, turns, and log; see own.
+*/
+func (g Game) replay(log []Entry) (Game, error) {
+	for i, entry := range log {
+		if entry.Seq != i+1 {
+			return g, fmt.Errorf("entry %d is numbered %d", i+1, entry.Seq)
+		}
+		if taken, ok := entry.Event.(TurnTaken); ok {
+			if err := g.checkTurn(taken.Turn); err != nil {
+				return g, fmt.Errorf("entry %d: %s", entry.Seq, err)
+			}
+		}
+		if err := g.fold(entry.Event); err != nil {
+			return g, fmt.Errorf("entry %d: %s", entry.Seq, err)
+		}
+		g.log = append(g.log, entry)
+	}
+
+	return g, nil
+}
+
+/*
+checkTurn returns an error if turn isn't what the current player would get from spinning its face.
+Turns taken in play are worked out by faceTurn to begin with, so only those read from a log need checking.
+*/
+func (g Game) checkTurn(turn Turn) error {
+	if err := g.playable(); err != nil {
+		return err
+	}
+
+	var (
+		player = g.players[g.current]
+		rules  = g.activeRules()
+	)
+	if turn.Player.id != player.id {
+		return fmt.Errorf("it's %s's turn, not %s's", player.Name, turn.Player.Name)
+	}
+	face, found := rules.face(turn.Face.ID)
+	if !found {
+		return fmt.Errorf("%q is not a face of this spinner", turn.Face.ID)
+	}
+	if expected, _ := faceTurn(player, face, rules); turn != expected {
+		return fmt.Errorf("%s spinning %s doesn't leave them as the turn says", player.Name, face)
+	}
+
+	return nil
+}
+
+/*
+commit folds e into g, appends it to the log, and notifies subscribers; if e can't happen now, g is left as it was.
+g must own its players, turns, and log; see own.
+*/
+func (g *Game) commit(e Event) error {
+	if err := g.fold(e); err != nil {
+		return err
+	}
+	if !g.unlogged {
+		g.log = append(g.log, Entry{Seq: len(g.log) + 1, Time: g.now(), Event: e})
+	}
+	g.emit(e)
+
+	return nil
+}
+
+/*
+fold changes g in place as e describes, or returns an error without changing g if e can't happen to g as it is.
+*/
+func (g *Game) fold(e Event) error {
+	switch e := e.(type) {
+	case PlayerAdded:
+		switch {
+		case g.phase != Setup:
+			return errors.New("players can't be added once the game has started")
+		case len(g.players) >= g.activeRules().MaxPlayers:
+			return fmt.Errorf("max player count of %d reached; unable to add a new player", g.activeRules().MaxPlayers)
+		case e.Player.id <= g.lastID:
+			return fmt.Errorf("%s has already been used", e.Player.id)
+		case e.Player.cherries != 0:
+			return fmt.Errorf("player %s can't have cherries before the game starts", e.Player.Name)
+		}
+		if err := g.checkPlayer(e.Player, -1); err != nil {
+			return err
+		}
+
+		g.players = append(g.players, e.Player)
+		g.lastID = e.Player.id
+	case PlayerRemoved:
+		seat := g.seatOf(e.Player.id)
+		switch {
+		case g.phase != Setup:
+			return errors.New("players can't be removed once the game has started")
+		case len(g.players) == 0:
+			return errors.New("no players to remove")
+		case seat < 0:
+			return fmt.Errorf("%s is not a current player", e.Player.id)
+		case g.players[seat] != e.Player:
+			return fmt.Errorf("player %s has changed since they were removed", e.Player.Name)
+		}
+
+		g.players = append(g.players[:seat], g.players[seat+1:]...)
+	case PlayerUpdated:
+		seat := g.seatOf(e.Player.id)
+		switch {
+		case g.phase != Setup:
+			return errors.New("players can't be changed once the game has started")
+		case seat < 0:
+			return fmt.Errorf("%s is not a current player", e.Player.id)
+		case g.players[seat] != e.Previous:
+			return fmt.Errorf("player %s has changed since they were updated", e.Previous.Name)
+		case e.Player.cherries != 0:
+			return fmt.Errorf("player %s can't have cherries before the game starts", e.Player.Name)
+		}
+		if err := g.checkPlayer(e.Player, seat); err != nil {
+			return err
+		}
+
+		g.players[seat] = e.Player
+	case SeatsChanged:
+		if g.phase != Setup {
+			return errors.New("players can't change seats once the game has started")
+		}
+		if len(e.Players) != len(g.players) {
+			return fmt.Errorf("%d players can't take %d seats", len(g.players), len(e.Players))
+		}
+		seated := make(map[PlayerID]bool, len(e.Players))
+		for _, player := range e.Players {
+			if seat := g.seatOf(player.id); seat < 0 || seated[player.id] || g.players[seat] != player {
+				return fmt.Errorf("player %s is not one of the players being seated", player.Name)
+			}
+			seated[player.id] = true
+		}
+
+		g.players = append([]Player(nil), e.Players...)
+	case TurnTaken:
+		if err := g.playable(); err != nil {
+			return err
+		}
+		var (
+			player = g.players[g.current]
+			rules  = g.activeRules()
+		)
+		switch {
+		case rules.Won(player.cherries):
+			return fmt.Errorf("%s has already won", player.Name)
+		case e.Number != len(g.turns)+1:
+			return fmt.Errorf("turn %d comes next, not turn %d", len(g.turns)+1, e.Number)
+		}
+
+		g.phase = InProgress
+		g.players[g.current] = e.Turn.Player
+		g.turns = append(g.turns, e.Turn)
+		if leader := g.outrightLeader(); leader >= 0 {
+			g.leader = g.players[leader].id
+		}
+		if !rules.Won(e.Turn.Player.cherries) {
+			g.current = (g.current + 1) % len(g.players)
+		}
+	case GameWon:
+		switch {
+		case g.phase != InProgress:
+			return fmt.Errorf("a game %s can't be won", g.phase)
+		case !g.activeRules().Won(g.players[g.current].cherries):
+			return fmt.Errorf("%s hasn't won", g.players[g.current].Name)
+		case e.Winner != g.players[g.current] || e.Turns != len(g.turns):
+			return fmt.Errorf("the game was won by %s in %d turns", g.players[g.current].Name, len(g.turns))
+		}
+
+		g.phase = Finished
+		g.winner = g.current
+	case GameReset:
+		for i := range g.players {
+			g.players[i].cherries = 0
+		}
+		g.current = 0
+		g.leader = 0
+		g.phase = Setup
+		g.turns = nil
+		g.winner = 0
+	default:
+		return fmt.Errorf("%T events aren't logged", e)
+	}
+
+	return nil
+}
+
+// checkPlayer returns an error if player's name, color, or age can't be used alongside everyone but the player in seat.
+func (g Game) checkPlayer(player Player, seat int) error {
+	others := g
+	if seat >= 0 {
+		others.players = make([]Player, 0, len(g.players)-1)
+		others.players = append(others.players, g.players[:seat]...)
+		others.players = append(others.players, g.players[seat+1:]...)
+	}
+
+	switch {
+	case player.Name == "":
+		return errors.New("must provide a valid name")
+	case others.nameTaken(player.Name):
+		return fmt.Errorf("there is already a player named %s", player.Name)
+	case !others.AvailableColors()[player.color]:
+		return fmt.Errorf("the %s color is not available", player.color)
+	case player.age < 0:
+		return fmt.Errorf("%d is not a valid age", player.age)
+	}
+
+	return nil
+}
@@ -0,0 +1,240 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// tickingClock returns a clock that starts at start and moves on a minute each time it's read.
+func tickingClock(start time.Time) func() time.Time {
+	next := start
+	return func() time.Time {
+		now := next
+		next = next.Add(time.Minute)
+		return now
+	}
+}
+
+func entryNames(log []Entry) []string {
+	events := make([]Event, len(log))
+	for i, entry := range log {
+		events[i] = entry.Event
+	}
+	return eventNames(events)
+}
+
+// playedGame returns a game won by Lori on its fifth turn, with a roster change before it started.
+func playedGame() Game {
+	start := time.Date(2022, time.August, 14, 9, 30, 0, 0, time.UTC)
+	g := Game{}.WithClock(tickingClock(start))
+	g, _ = g.AddPlayer("Lori", Blue)
+	g, _ = g.AddPlayer("Clinton", Yellow)
+	g, _ = g.AddPlayer("Gabriel", Green)
+	g, _ = g.RemovePlayer(g.Players()[2].ID())
+
+	// Lori +4, Clinton +1, Lori +4, Clinton spills 1, Lori +2 and wins
+	spins := fixedSpinner{3, 0, 3, 6, 1}
+	g = g.WithSpinner(&spins)
+	for g.Phase() != Finished {
+		g, _, _, _ = g.NextTurn()
+	}
+
+	return g
+}
+
+func TestGameLog(t *testing.T) {
+	var (
+		g     = playedGame()
+		log   = g.Log()
+		start = time.Date(2022, time.August, 14, 9, 30, 0, 0, time.UTC)
+	)
+
+	expected := []string{
+		"PlayerAdded", "PlayerAdded", "PlayerAdded", "PlayerRemoved",
+		"TurnTaken", "TurnTaken", "TurnTaken", "TurnTaken", "TurnTaken",
+		"GameWon",
+	}
+	if actual := entryNames(log); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %v but got %v", expected, actual)
+	}
+	for i, entry := range log {
+		if entry.Seq != i+1 {
+			t.Fatalf("expected entry %d to be numbered %d but got %d", i+1, i+1, entry.Seq)
+		}
+		if expected := start.Add(time.Duration(i) * time.Minute); !entry.Time.Equal(expected) {
+			t.Fatalf("expected entry %d at %s but got %s", entry.Seq, expected, entry.Time)
+		}
+	}
+
+	// the log returned is a copy
+	log[0].Seq = 20
+	if g.Log()[0].Seq != 1 {
+		t.Fatal("changing the returned log shouldn't change the game")
+	}
+}
+
+func TestGameLogUnchangedByRejects(t *testing.T) {
+	g := Game{}
+	g, _ = g.AddPlayer("Lori", Blue)
+	g, _ = g.AddPlayer("Lori", Green)
+	g, _ = g.UpdatePlayer(g.Players()[0].ID(), "", Blue)
+	g, _ = g.SetPlayerAge(g.Players()[0].ID(), 0)
+
+	if actual := entryNames(g.Log()); !reflect.DeepEqual(actual, []string{"PlayerAdded"}) {
+		t.Fatalf("rejected and empty changes shouldn't be logged; got %v", actual)
+	}
+}
+
+func TestGameAt(t *testing.T) {
+	g := playedGame()
+
+	empty, err := g.At(0)
+	switch {
+	case err != nil:
+		t.Fatalf("failed to go back to the start: %s", err)
+	case empty.PlayerCount() != 0 || len(empty.Log()) != 0:
+		t.Fatalf("expected an empty game at the start but got %+v", empty)
+	}
+
+	setup, err := g.At(4)
+	switch {
+	case err != nil:
+		t.Fatalf("failed to go back to entry 4: %s", err)
+	case setup.Phase() != Setup || len(setup.Log()) != 4:
+		t.Fatalf("expected the game before its first turn but got %+v", setup)
+	case setup.PlayerCount() != 2 || setup.Players()[1].Name != "Clinton":
+		t.Fatalf("expected Lori and Clinton to be seated but got %+v", setup.Players())
+	}
+
+	// Clinton has just spilled the bucket, and Lori is up next
+	spilled, err := g.At(8)
+	switch {
+	case err != nil:
+		t.Fatalf("failed to go back to entry 8: %s", err)
+	case len(spilled.Turns()) != 4 || spilled.Players()[1].Cherries() != 0:
+		t.Fatalf("expected Clinton to have spilled on turn 4 but got %+v", spilled.Turns())
+	}
+	if current, _ := spilled.CurrentPlayer(); current.Name != "Lori" {
+		t.Fatalf("expected Lori to be up next but got %s", current.Name)
+	}
+
+	// a past game can be played again from where it was
+	spins := fixedSpinner{1}
+	replayed, _, over, err := spilled.WithSpinner(&spins).NextTurn()
+	if err != nil || !over {
+		t.Fatalf("expected Lori to win again but got over %t and error %v", over, err)
+	}
+	if actual := replayed.Log(); !reflect.DeepEqual(entryNames(actual), entryNames(g.Log())) {
+		t.Fatalf("expected the same log again but got %v", entryNames(actual))
+	}
+
+	last, err := g.At(len(g.Log()))
+	if err != nil {
+		t.Fatalf("failed to rebuild the finished game: %s", err)
+	}
+	if !sameState(last, g) || !reflect.DeepEqual(last.Log(), g.Log()) {
+		t.Fatalf("the whole log should add up to the game\nexpected: %+v\nactual:   %+v", g, last)
+	}
+
+	for _, seq := range []int{-1, len(g.Log()) + 1} {
+		if _, err := g.At(seq); err == nil {
+			t.Fatalf("shouldn't be able to go to entry %d", seq)
+		}
+	}
+}
+
+func TestGameResetLogged(t *testing.T) {
+	g := playedGame()
+	finished := len(g.Log())
+
+	g = g.Reset()
+	if log := g.Log(); len(log) != finished+1 || entryNames(log[finished:])[0] != "GameReset" {
+		t.Fatalf("expected the reset to be logged after the finished game but got %v", entryNames(log))
+	}
+
+	before, err := g.At(finished)
+	if err != nil {
+		t.Fatalf("failed to go back to before the reset: %s", err)
+	}
+	if winner, ok := before.Winner(); !ok || winner.Name != "Lori" {
+		t.Fatalf("expected Lori to have won before the reset but got %+v", before)
+	}
+}
+
+func TestRebuild(t *testing.T) {
+	g := playedGame()
+
+	rebuilt, err := Rebuild(StandardRules(), g.Log())
+	if err != nil {
+		t.Fatalf("failed to rebuild the game: %s", err)
+	}
+	if winner, _ := rebuilt.Winner(); winner.Name != "Lori" || len(rebuilt.Turns()) != 5 {
+		t.Fatalf("expected Lori to win in 5 turns but got %+v", rebuilt)
+	}
+
+	if _, err := Rebuild(Ruleset{}, g.Log()); err == nil {
+		t.Fatal("shouldn't be able to rebuild under invalid rules")
+	}
+}
+
+func TestRebuildInvalid(t *testing.T) {
+	testCases := map[string]func(log []Entry) []Entry{
+		"misnumbered": func(log []Entry) []Entry {
+			log[2].Seq = 7
+			return log
+		},
+		"missing entry": func(log []Entry) []Entry {
+			return append(log[:4], log[5:]...)
+		},
+		"reused ID": func(log []Entry) []Entry {
+			added := log[2].Event.(PlayerAdded)
+			added.Player.id = 1
+			log[2].Event = added
+			return log
+		},
+		"removed stranger": func(log []Entry) []Entry {
+			removed := log[3].Event.(PlayerRemoved)
+			removed.Player.Name = "Sergio"
+			log[3].Event = removed
+			return log
+		},
+		"wrong player": func(log []Entry) []Entry {
+			taken := log[4].Event.(TurnTaken)
+			taken.Turn.Player = log[1].Event.(PlayerAdded).Player
+			log[4].Event = taken
+			return log
+		},
+		"wrong cherries": func(log []Entry) []Entry {
+			taken := log[4].Event.(TurnTaken)
+			taken.Turn.Player.cherries = 9
+			log[4].Event = taken
+			return log
+		},
+		"unknown face": func(log []Entry) []Entry {
+			taken := log[4].Event.(TurnTaken)
+			taken.Turn.Face.ID = "pick-7"
+			log[4].Event = taken
+			return log
+		},
+		"early win": func(log []Entry) []Entry {
+			log[5].Event = GameWon{Winner: log[0].Event.(PlayerAdded).Player, Turns: 1}
+			return log
+		},
+		"turn after win": func(log []Entry) []Entry {
+			return append(log[:9], Entry{Seq: 10, Event: log[4].Event})
+		},
+		"unlogged event": func(log []Entry) []Entry {
+			log[4].Event = BucketSpilled{}
+			return log
+		},
+	}
+
+	for name, tamper := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Rebuild(StandardRules(), tamper(playedGame().Log())); err == nil {
+				t.Fatal("shouldn't be able to rebuild from a tampered log")
+			}
+		})
+	}
+}
@@ -12,8 +12,19 @@ const (
 	Green
 	Red
 	Yellow
+	Cyan
+	Magenta
+	Orange
+	Purple
 )
 
+var colors = [...]Color{Blue, Green, Red, Yellow, Cyan, Magenta, Orange, Purple}
+
+// Colors returns every valid player color in display order.
+func Colors() []Color {
+	return append([]Color(nil), colors[:]...)
+}
+
 func (c Color) String() string {
 	switch c {
 	case Blue:
@@ -24,6 +35,14 @@ func (c Color) String() string {
 		return "red"
 	case Yellow:
 		return "yellow"
+	case Cyan:
+		return "cyan"
+	case Magenta:
+		return "magenta"
+	case Orange:
+		return "orange"
+	case Purple:
+		return "purple"
 	default:
 		return fmt.Sprintf("%d", int(c))
 	}
@@ -33,24 +52,38 @@ func (c Color) FilterValue() string {
 	return c.String()
 }
 
+// PlayerID identifies a player for the life of a Game, even if their name or seat changes.
+type PlayerID int
+
+func (id PlayerID) String() string {
+	return fmt.Sprintf("player #%d", int(id))
+}
+
 type Player struct {
 	Name string
 
+	// Age in years, or 0 if it isn't known.
+	age      int
 	cherries int
 	color    Color
+	id       PlayerID
 }
 
-func (p Player) updateCherries(amount int) Player {
-	p.cherries += amount
+func (p Player) updateCherries(amount int, rules Ruleset) Player {
+	p.cherries = rules.apply(p.cherries, amount)
+	return p
+}
 
-	switch {
-	case p.cherries < 0:
-		p.cherries = 0
-	case p.cherries > WinningScore:
-		p.cherries = WinningScore
-	}
+func (p Player) Age() int {
+	return p.age
+}
 
-	return p
+func (p Player) Cherries() int {
+	return p.cherries
+}
+
+func (p Player) ID() PlayerID {
+	return p.id
 }
 
 func (p Player) Color() Color {
@@ -7,10 +7,14 @@ import (
 
 var (
 	colorTestValues = map[Color]string{
-		Blue:   "blue",
-		Green:  "green",
-		Red:    "red",
-		Yellow: "yellow",
+		Blue:    "blue",
+		Green:   "green",
+		Red:     "red",
+		Yellow:  "yellow",
+		Cyan:    "cyan",
+		Magenta: "magenta",
+		Orange:  "orange",
+		Purple:  "purple",
 	}
 )
 
@@ -161,7 +165,7 @@ func TestPlayerUpdateCherries(t *testing.T) {
 				Name: test.name,
 			}
 			for _, spin := range test.spins {
-				player = player.updateCherries(spin)
+				player = player.updateCherries(spin, StandardRules())
 			}
 			if player.cherries != test.expected {
 				t.Fatalf("expected %d but got %d", test.expected, player.cherries)
@@ -169,3 +173,40 @@ func TestPlayerUpdateCherries(t *testing.T) {
 		})
 	}
 }
+
+func TestPlayerID(t *testing.T) {
+	testCases := map[PlayerID]string{
+		1:  "player #1",
+		7:  "player #7",
+		42: "player #42",
+	}
+
+	for id, expected := range testCases {
+		t.Run(expected, func(t *testing.T) {
+			player := Player{
+				Name: "Myra",
+				id:   id,
+			}
+			if actual := player.ID(); actual != id {
+				t.Fatalf("expected %d but got %d", id, actual)
+			}
+			if actual := id.String(); actual != expected {
+				t.Fatalf("expected %s but got %s", expected, actual)
+			}
+		})
+	}
+}
+
+func TestPlayerCherries(t *testing.T) {
+	for _, cherries := range []int{0, 3, 10} {
+		t.Run(fmt.Sprintf("%d cherries", cherries), func(t *testing.T) {
+			player := Player{
+				Name:     "Myra",
+				cherries: cherries,
+			}
+			if actual := player.Cherries(); actual != cherries {
+				t.Fatalf("expected %d but got %d", cherries, actual)
+			}
+		})
+	}
+}
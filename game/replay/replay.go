@@ -0,0 +1,142 @@
+/*
+Package replay records seeded games so that they can be checked and watched again later.
+
+A replay stores the game as it was set up, the seed for its spinner, and every turn that was taken.
+Because a seeded spinner always produces the same spins, the recorded turns can be verified by playing the game again.
+*/
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bmoller/cherry-o/game"
+)
+
+// FormatVersion is the version of the replay file format written by Write.
+const FormatVersion = 1
+
+type Replay struct {
+	Version int `json:"version"`
+	// Seed for the spinner that produced Turns.
+	Seed int64 `json:"seed"`
+	// Game holds the rules and roster as they were before the first spin.
+	Game game.Game `json:"game"`
+	// Turns is every turn of the game in order.
+	Turns []game.Turn `json:"turns"`
+}
+
+/*
+Record plays g to completion with a spinner seeded from seed and returns the replay of that game.
+g must not have started yet.
+*/
+func Record(g game.Game, seed int64) (Replay, error) {
+	if g.Phase() != game.Setup {
+		return Replay{}, fmt.Errorf("can only record a game from the start; this game is %s", g.Phase())
+	}
+
+	start := g.WithSpinner(nil)
+	turns, _, err := g.WithSpinner(game.NewSeededSpinner(seed)).Play()
+	if err != nil {
+		return Replay{}, err
+	}
+
+	return Replay{
+		Version: FormatVersion,
+		Seed:    seed,
+		Game:    start,
+		Turns:   turns,
+	}, nil
+}
+
+/*
+Verify plays the game in r again from its seed and checks that every turn matches the recording.
+It returns the finished game if they all match, or an error describing the first difference.
+*/
+func (r Replay) Verify() (game.Game, error) {
+	var (
+		err  error
+		g    = r.Game.WithSpinner(game.NewSeededSpinner(r.Seed))
+		over bool
+		turn game.Turn
+	)
+
+	if g.Phase() != game.Setup {
+		return g, fmt.Errorf("the recorded game should start in setup but is %s", g.Phase())
+	}
+
+	for i, recorded := range r.Turns {
+		if over {
+			return g, fmt.Errorf("the recording has %d turns but the game was won on turn %d", len(r.Turns), i)
+		}
+		if g, turn, over, err = g.NextTurn(); err != nil {
+			return g, fmt.Errorf("failed to replay turn %d: %s", i+1, err)
+		}
+		if turn != recorded {
+			return g, fmt.Errorf("turn %d doesn't match: recorded %s spinning %s, but the seed gives %s spinning %s",
+				i+1, recorded.Player, recorded.Face, turn.Player, turn.Face)
+		}
+	}
+	if !over {
+		return g, fmt.Errorf("the recording ends after %d turns without a winner", len(r.Turns))
+	}
+
+	return g.WithSpinner(nil), nil
+}
+
+// Winner returns the player who took the last recorded turn.
+func (r Replay) Winner() (game.Player, bool) {
+	if len(r.Turns) == 0 {
+		return game.Player{}, false
+	}
+	return r.Turns[len(r.Turns)-1].Player, true
+}
+
+func (r Replay) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+func Read(reader io.Reader) (Replay, error) {
+	var r Replay
+	if err := json.NewDecoder(reader).Decode(&r); err != nil {
+		return r, fmt.Errorf("failed to read replay: %s", err)
+	}
+
+	switch {
+	case r.Version != FormatVersion:
+		return r, fmt.Errorf("unsupported replay format version %d; expected %d", r.Version, FormatVersion)
+	case len(r.Turns) == 0:
+		return r, errors.New("the replay has no turns")
+	}
+
+	return r, nil
+}
+
+// Save writes r to the file at path, replacing anything already there.
+func (r Replay) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err = r.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func Load(path string) (Replay, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Replay{}, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
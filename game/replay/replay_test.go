@@ -0,0 +1,144 @@
+package replay
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bmoller/cherry-o/game"
+)
+
+func newGame(t *testing.T) game.Game {
+	t.Helper()
+
+	g, err := game.New(game.StandardRules())
+	if err != nil {
+		t.Fatalf("failed to create game: %s", err)
+	}
+	for name, color := range map[string]game.Color{
+		"Sergio": game.Blue,
+		"Traci":  game.Green,
+		"Erik":   game.Red,
+	} {
+		if g, err = g.AddPlayer(name, color); err != nil {
+			t.Fatalf("failed to add %s: %s", name, err)
+		}
+	}
+
+	return g
+}
+
+func TestRecordVerify(t *testing.T) {
+	g := newGame(t)
+
+	for seed := int64(1); seed <= 10; seed++ {
+		r, err := Record(g, seed)
+		if err != nil {
+			t.Fatalf("failed to record seed %d: %s", seed, err)
+		}
+
+		var buf bytes.Buffer
+		if err = r.Write(&buf); err != nil {
+			t.Fatalf("failed to write replay: %s", err)
+		}
+		loaded, err := Read(&buf)
+		if err != nil {
+			t.Fatalf("failed to read replay: %s", err)
+		}
+		if !reflect.DeepEqual(loaded, r) {
+			t.Fatalf("replay changed when written and read back")
+		}
+
+		finished, err := loaded.Verify()
+		if err != nil {
+			t.Fatalf("failed to verify seed %d: %s", seed, err)
+		}
+		winner, _ := finished.Winner()
+		if recorded, _ := loaded.Winner(); recorded != winner {
+			t.Fatalf("expected %s to win but got %s", recorded, winner)
+		}
+	}
+}
+
+func TestRecordStarted(t *testing.T) {
+	g, _, _, _ := newGame(t).WithSpinner(game.NewSeededSpinner(1)).NextTurn()
+	if _, err := Record(g, 1); err == nil {
+		t.Fatal("shouldn't be able to record a game that has already started")
+	}
+}
+
+func TestVerifyTampered(t *testing.T) {
+	r, err := Record(newGame(t), 4)
+	if err != nil {
+		t.Fatalf("failed to record: %s", err)
+	}
+
+	testCases := map[string]func(Replay) Replay{
+		"different seed": func(r Replay) Replay {
+			r.Seed++
+			return r
+		},
+		"changed face": func(r Replay) Replay {
+			r.Turns = append([]game.Turn(nil), r.Turns...)
+			if r.Turns[0].Face.ID == game.BucketID {
+				r.Turns[0].Face = game.PickFace(4)
+			} else {
+				r.Turns[0].Face = game.BucketFace
+			}
+			return r
+		},
+		"missing turns": func(r Replay) Replay {
+			r.Turns = r.Turns[:len(r.Turns)-1]
+			return r
+		},
+		"extra turns": func(r Replay) Replay {
+			r.Turns = append(append([]game.Turn(nil), r.Turns...), r.Turns[0])
+			return r
+		},
+	}
+
+	for name, tamper := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := tamper(r).Verify(); err == nil {
+				t.Fatal("a tampered replay should fail verification")
+			}
+		})
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	testCases := map[string]string{
+		"not json":    "cherries",
+		"old version": `{"version":0,"turns":[{}]}`,
+		"no turns":    `{"version":1,"turns":[]}`,
+	}
+
+	for name, input := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Read(strings.NewReader(input)); err == nil {
+				t.Fatalf("shouldn't be able to read %s", input)
+			}
+		})
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	r, err := Record(newGame(t), 9)
+	if err != nil {
+		t.Fatalf("failed to record: %s", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "replay.json")
+	if err = r.Save(path); err != nil {
+		t.Fatalf("failed to save: %s", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("failed to load: %s", err)
+	}
+	if _, err = loaded.Verify(); err != nil {
+		t.Fatalf("failed to verify loaded replay: %s", err)
+	}
+}
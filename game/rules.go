@@ -0,0 +1,194 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Overflow decides what happens when a spin would take a player past the target score.
+type Overflow int
+
+const (
+	// Clamp discards any cherries beyond the target; reaching the target wins.
+	Clamp Overflow = iota
+	// Bounce requires landing on the target exactly; any excess is taken back off the target.
+	Bounce
+	// Overshoot keeps every cherry; reaching or passing the target wins.
+	Overshoot
+)
+
+func (o Overflow) String() string {
+	switch o {
+	case Clamp:
+		return "clamp"
+	case Bounce:
+		return "bounce"
+	case Overshoot:
+		return "overshoot"
+	default:
+		return fmt.Sprintf("%d", int(o))
+	}
+}
+
+func ParseOverflow(s string) (Overflow, error) {
+	for _, o := range []Overflow{Clamp, Bounce, Overshoot} {
+		if strings.EqualFold(s, o.String()) {
+			return o, nil
+		}
+	}
+
+	return Clamp, fmt.Errorf("unknown overflow policy %q; expected clamp, bounce, or overshoot", s)
+}
+
+// Ruleset holds the variant being played; the zero value is not valid, start from StandardRules instead.
+type Ruleset struct {
+	TargetScore int      `json:"targetScore"`
+	Faces       []Face   `json:"faces"`
+	Overflow    Overflow `json:"overflow"`
+	MaxPlayers  int      `json:"maxPlayers"`
+	// ShareColors lets more than one player use the same color, for games with more players than colors.
+	ShareColors bool `json:"shareColors"`
+}
+
+var standardRules = Ruleset{
+	TargetScore: WinningScore,
+	Faces:       standardFaces[:],
+	Overflow:    Clamp,
+	MaxPlayers:  MaxPlayers,
+}
+
+// StandardRules returns the rules printed on the box.
+func StandardRules() Ruleset {
+	return standardRules.clone()
+}
+
+func (r Ruleset) clone() Ruleset {
+	r.Faces = append([]Face(nil), r.Faces...)
+	return r
+}
+
+func (r Ruleset) Validate() error {
+	var (
+		canScore bool
+		ids      = make(map[FaceID]bool)
+	)
+	for _, face := range r.Faces {
+		switch {
+		case face.ID == "":
+			return errors.New("every spinner face needs an ID")
+		case ids[face.ID]:
+			return fmt.Errorf("spinner face %s appears more than once", face.ID)
+		case face.Effect > 0 && !face.Spill:
+			canScore = true
+		}
+		ids[face.ID] = true
+	}
+
+	switch {
+	case r.TargetScore <= 0:
+		return errors.New("target score must be at least 1")
+	case len(r.Faces) == 0:
+		return errors.New("the spinner needs at least one face")
+	case !canScore:
+		return errors.New("the spinner needs at least one face that adds cherries")
+	case r.MaxPlayers <= 0:
+		return errors.New("max players must be at least 1")
+	case r.MaxPlayers > len(colors) && !r.ShareColors:
+		return fmt.Errorf("only %d players can have a color of their own; share colors to allow %d players", len(colors), r.MaxPlayers)
+	case r.Overflow < Clamp || r.Overflow > Overshoot:
+		return fmt.Errorf("unknown overflow policy %s", r.Overflow)
+	}
+	if stuck, ok := r.stuckScore(); ok {
+		return fmt.Errorf("a player with %d cherries could never land on exactly %d", stuck, r.TargetScore)
+	}
+
+	return nil
+}
+
+/*
+stuckScore returns a score that a player can reach from an empty bucket but never win from, if there is one.
+A game that gets there would go on forever, which can only happen when bouncing: otherwise every scoring spin brings a
+player closer to the target, and they end up there by clamping or passing it.
+*/
+func (r Ruleset) stuckScore() (int, bool) {
+	if r.Overflow != Bounce {
+		return 0, false
+	}
+
+	// bouncing keeps every score between 0 and the target, so those are all the scores there are
+	reached := make([]bool, r.TargetScore+1)
+	reached[0] = true
+	for pending := []int{0}; len(pending) > 0; pending = pending[1:] {
+		for _, face := range r.Faces {
+			if next := r.Apply(pending[0], face); !reached[next] {
+				reached[next] = true
+				pending = append(pending, next)
+			}
+		}
+	}
+
+	// work back from the target to find every score that can still win
+	winnable := make([]bool, r.TargetScore+1)
+	winnable[r.TargetScore] = true
+	for changed := true; changed; {
+		changed = false
+		for cherries := range winnable {
+			for _, face := range r.Faces {
+				if !winnable[cherries] && winnable[r.Apply(cherries, face)] {
+					winnable[cherries] = true
+					changed = true
+				}
+			}
+		}
+	}
+
+	for cherries := range reached {
+		if reached[cherries] && !winnable[cherries] {
+			return cherries, true
+		}
+	}
+	return 0, false
+}
+
+// apply returns the cherry count after adding amount to cherries under r's overflow policy.
+func (r Ruleset) apply(cherries, amount int) int {
+	cherries += amount
+
+	if cherries > r.TargetScore {
+		switch r.Overflow {
+		case Clamp:
+			cherries = r.TargetScore
+		case Bounce:
+			cherries = 2*r.TargetScore - cherries
+		}
+	}
+	if cherries < 0 {
+		cherries = 0
+	}
+
+	return cherries
+}
+
+// Apply returns the number of cherries a player holding cherries has after spinning face.
+func (r Ruleset) Apply(cherries int, face Face) int {
+	return r.apply(cherries, face.amount(cherries))
+}
+
+// Won reports whether holding cherries wins the game.
+func (r Ruleset) Won(cherries int) bool {
+	if r.Overflow == Overshoot {
+		return cherries >= r.TargetScore
+	}
+	return cherries == r.TargetScore
+}
+
+// face returns the face of r's spinner with id.
+func (r Ruleset) face(id FaceID) (Face, bool) {
+	for _, face := range r.Faces {
+		if face.ID == id {
+			return face, true
+		}
+	}
+	return Face{}, false
+}
@@ -0,0 +1,203 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestOverflowString(t *testing.T) {
+	testCases := map[Overflow]string{
+		Clamp:     "clamp",
+		Bounce:    "bounce",
+		Overshoot: "overshoot",
+	}
+
+	for overflow, name := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if actual := overflow.String(); actual != name {
+				t.Fatalf("expected %s but got %s", name, actual)
+			}
+			if parsed, err := ParseOverflow(name); err != nil || parsed != overflow {
+				t.Fatalf("expected %s to parse as %d but got %d (%v)", name, overflow, parsed, err)
+			}
+		})
+	}
+}
+
+func TestParseOverflowInvalid(t *testing.T) {
+	if _, err := ParseOverflow("wrap"); err == nil {
+		t.Fatal("shouldn't be able to parse an unknown overflow policy")
+	}
+}
+
+func TestRulesetValidate(t *testing.T) {
+	testCases := map[string]Ruleset{
+		"zero target": {
+			TargetScore: 0,
+			Faces:       []Face{PickFace(1)},
+		},
+		"no faces": {
+			TargetScore: 10,
+		},
+		"no scoring faces": {
+			TargetScore: 10,
+			Faces:       []Face{BirdFace, BucketFace},
+		},
+		"missing face ID": {
+			TargetScore: 10,
+			Faces:       []Face{{Name: "mystery", Effect: 1}},
+		},
+		"duplicate faces": {
+			TargetScore: 10,
+			Faces:       []Face{PickFace(1), PickFace(1)},
+		},
+		"no players": {
+			TargetScore: 10,
+			Faces:       []Face{PickFace(1)},
+		},
+		"more players than colors": {
+			TargetScore: 10,
+			Faces:       []Face{PickFace(1)},
+			MaxPlayers:  len(colors) + 1,
+		},
+		"target out of reach": {
+			TargetScore: 3,
+			Faces:       []Face{PickFace(2)},
+			Overflow:    Bounce,
+			MaxPlayers:  4,
+		},
+		"unknown overflow": {
+			TargetScore: 10,
+			Faces:       []Face{PickFace(1)},
+			Overflow:    Overshoot + 1,
+			MaxPlayers:  4,
+		},
+	}
+
+	if err := StandardRules().Validate(); err != nil {
+		t.Fatalf("standard rules should be valid: %s", err)
+	}
+	bounce := StandardRules()
+	bounce.Overflow = Bounce
+	if err := bounce.Validate(); err != nil {
+		t.Fatalf("standard rules should be valid when bouncing: %s", err)
+	}
+	for name, rules := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if err := rules.Validate(); err == nil {
+				t.Fatal("ruleset should be invalid")
+			}
+			if _, err := New(rules); err == nil {
+				t.Fatal("shouldn't be able to create a game with an invalid ruleset")
+			}
+		})
+	}
+}
+
+func TestStandardRulesIsolated(t *testing.T) {
+	rules := StandardRules()
+	rules.Faces[0] = PickFace(100)
+	if actual := StandardRules().Faces[0].Effect; actual != 1 {
+		t.Fatalf("modifying a copy changed the standard rules; first face is now %d", actual)
+	}
+}
+
+func TestRulesetApply(t *testing.T) {
+	testCases := []struct {
+		overflow Overflow
+		spins    []int
+		expected int
+		won      bool
+	}{
+		{Clamp, []int{4, 4, 4}, 10, true},
+		{Clamp, []int{4, -10, 3}, 3, false},
+		{Bounce, []int{4, 4, 4}, 8, false},
+		{Bounce, []int{4, 4, 2}, 10, true},
+		{Bounce, []int{4, 4, 4, 2}, 10, true},
+		{Bounce, []int{4, 4, 1, 4, -2}, 5, false},
+		{Overshoot, []int{4, 4, 4}, 12, true},
+		{Overshoot, []int{4, 4, 4, -2, -2}, 8, false},
+		{Overshoot, []int{-2, 1}, 1, false},
+	}
+
+	for _, test := range testCases {
+		t.Run(fmt.Sprintf("%s %v", test.overflow, test.spins), func(t *testing.T) {
+			rules := StandardRules()
+			rules.Overflow = test.overflow
+
+			var cherries int
+			for _, spin := range test.spins {
+				cherries = rules.apply(cherries, spin)
+			}
+			if cherries != test.expected {
+				t.Fatalf("expected %d cherries but got %d", test.expected, cherries)
+			}
+			if won := rules.Won(cherries); won != test.won {
+				t.Fatalf("expected won to be %t with %d cherries", test.won, cherries)
+			}
+		})
+	}
+}
+
+func TestGamePlayCustomRules(t *testing.T) {
+	for _, overflow := range []Overflow{Clamp, Bounce, Overshoot} {
+		t.Run(overflow.String(), func(t *testing.T) {
+			rules := Ruleset{
+				TargetScore: 5,
+				Faces:       []Face{PickFace(1), PickFace(2), {ID: "worm", Name: "worm", Effect: -1}},
+				Overflow:    overflow,
+				MaxPlayers:  2,
+			}
+			g, err := New(rules)
+			if err != nil {
+				t.Fatalf("failed to create game: %s", err)
+			}
+			g, _ = g.AddPlayer("Traci", Green)
+			g, _ = g.AddPlayer("Erik", Red)
+
+			for seed := int64(0); seed < 50; seed++ {
+				turns, winner, err := g.WithSpinner(NewSeededSpinner(seed)).Play()
+				switch {
+				case err != nil:
+					t.Fatalf("unexpected error: %s", err)
+				case !rules.Won(winner.cherries):
+					t.Fatalf("winner %s has only %d cherries", winner.Name, winner.cherries)
+				case overflow != Overshoot && winner.cherries != 5:
+					t.Fatalf("expected winner to have exactly 5 cherries but got %d", winner.cherries)
+				case len(turns) < 5:
+					t.Fatalf("too few turns to have a winner; got %d turns", len(turns))
+				}
+				for _, turn := range turns {
+					if turn.Face.Effect != 1 && turn.Face.Effect != 2 && turn.Face.Effect != -1 {
+						t.Fatalf("turn has a face not on the spinner: %s", turn.Face.ID)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRulesetApplyFace(t *testing.T) {
+	rules := StandardRules()
+	rules.TargetScore = 20
+
+	testCases := []struct {
+		face     Face
+		cherries int
+		expected int
+	}{
+		{PickFace(4), 15, 19},
+		{PickFace(4), 19, 20},
+		{BirdFace, 1, 0},
+		{DogFace, 12, 10},
+		{BucketFace, 17, 0},
+	}
+
+	for _, test := range testCases {
+		t.Run(fmt.Sprintf("%s with %d", test.face, test.cherries), func(t *testing.T) {
+			if actual := rules.Apply(test.cherries, test.face); actual != test.expected {
+				t.Fatalf("expected %d but got %d", test.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+/*
+Package sim plays many headless games of Cherry-O to estimate how the game behaves under a given ruleset.
+*/
+package sim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+
+	"github.com/bmoller/cherry-o/game"
+)
+
+// Config describes a batch of simulated games.
+type Config struct {
+	// Number of players in every game.
+	Players int
+	// Number of games to play.
+	Games int
+	// Rules for every game; the roster limit and color sharing are relaxed as needed to seat Players.
+	Rules game.Ruleset
+	// Seed for the spinners; results are reproducible for the same Seed and Workers.
+	Seed int64
+	// Number of games played concurrently; runtime.NumCPU() is used when zero.
+	Workers int
+}
+
+// Histogram counts how often each value was observed.
+type Histogram map[int]int
+
+func (h Histogram) add(other Histogram) {
+	for value, count := range other {
+		h[value] += count
+	}
+}
+
+func (h Histogram) Total() int {
+	var total int
+	for _, count := range h {
+		total += count
+	}
+	return total
+}
+
+// Values returns the observed values in increasing order.
+func (h Histogram) Values() []int {
+	values := make([]int, 0, len(h))
+	for value := range h {
+		values = append(values, value)
+	}
+	sort.Ints(values)
+
+	return values
+}
+
+func (h Histogram) Mean() float64 {
+	var sum, total int
+	for value, count := range h {
+		sum += value * count
+		total += count
+	}
+	if total == 0 {
+		return 0
+	}
+
+	return float64(sum) / float64(total)
+}
+
+// Percentile returns the smallest observed value that at least p percent of observations do not exceed.
+func (h Histogram) Percentile(p float64) int {
+	var (
+		seen   int
+		target = p / 100 * float64(h.Total())
+		values = h.Values()
+	)
+
+	for _, value := range values {
+		seen += h[value]
+		if float64(seen) >= target {
+			return value
+		}
+	}
+	if len(values) == 0 {
+		return 0
+	}
+
+	return values[len(values)-1]
+}
+
+// Result summarizes a batch of simulated games.
+type Result struct {
+	Games   int
+	Players int
+	Seed    int64
+	// Game length in turns, counting every player's spin.
+	Turns Histogram
+	// Game length in rounds, where a round is one spin by each player; the last round may be partial.
+	Rounds Histogram
+	// Number of games won from each seat, starting with the first player.
+	SeatWins []int
+	// Number of times each face was spun.
+	Faces map[game.FaceID]int
+}
+
+func newResult(players int) Result {
+	return Result{
+		Players:  players,
+		Turns:    make(Histogram),
+		Rounds:   make(Histogram),
+		SeatWins: make([]int, players),
+		Faces:    make(map[game.FaceID]int),
+	}
+}
+
+func (r *Result) add(other Result) {
+	r.Games += other.Games
+	r.Turns.add(other.Turns)
+	r.Rounds.add(other.Rounds)
+	for seat, wins := range other.SeatWins {
+		r.SeatWins[seat] += wins
+	}
+	for face, count := range other.Faces {
+		r.Faces[face] += count
+	}
+}
+
+// WinRate returns the fraction of games won from seat, counting from zero.
+func (r Result) WinRate(seat int) float64 {
+	if r.Games == 0 || seat < 0 || seat >= len(r.SeatWins) {
+		return 0
+	}
+	return float64(r.SeatWins[seat]) / float64(r.Games)
+}
+
+// FaceRate returns the fraction of all spins that landed on face.
+func (r Result) FaceRate(face game.FaceID) float64 {
+	var total int
+	for _, count := range r.Faces {
+		total += count
+	}
+	if total == 0 {
+		return 0
+	}
+
+	return float64(r.Faces[face]) / float64(total)
+}
+
+/*
+Roster builds a game with players seated under rules, named by seat number.
+Colors are irrelevant to a headless game, so rules are relaxed to share colors and seat everyone.
+*/
+func Roster(players int, rules game.Ruleset) (game.Game, error) {
+	if rules.MaxPlayers < players {
+		rules.MaxPlayers = players
+	}
+	rules.ShareColors = true
+
+	g, err := game.New(rules)
+	if err != nil {
+		return g, err
+	}
+
+	colors := game.Colors()
+	for i := 0; i < players; i++ {
+		if g, err = g.AddPlayer(fmt.Sprintf("Seat %d", i+1), colors[i%len(colors)]); err != nil {
+			return g, err
+		}
+	}
+
+	return g, nil
+}
+
+/*
+Run plays cfg.Games games across cfg.Workers goroutines and summarizes them.
+If ctx is cancelled before every game is played, the games finished so far are returned along with ctx's error.
+*/
+func Run(ctx context.Context, cfg Config) (Result, error) {
+	switch {
+	case cfg.Players <= 0:
+		return Result{}, errors.New("need at least one player to simulate")
+	case cfg.Games <= 0:
+		return Result{}, errors.New("need at least one game to simulate")
+	}
+
+	g, err := Roster(cfg.Players, cfg.Rules)
+	if err != nil {
+		return Result{}, err
+	}
+
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > cfg.Games {
+		workers = cfg.Games
+	}
+
+	results := make(chan workerResult, workers)
+	for w := 0; w < workers; w++ {
+		// split the games evenly, handing any remainder to the first workers
+		games := cfg.Games / workers
+		if w < cfg.Games%workers {
+			games++
+		}
+		go func(w, games int) {
+			result, err := play(ctx, g.WithSpinner(game.NewSeededSpinner(cfg.Seed+int64(w))), games)
+			results <- workerResult{result, err}
+		}(w, games)
+	}
+
+	result := newResult(cfg.Players)
+	result.Seed = cfg.Seed
+	for w := 0; w < workers; w++ {
+		partial := <-results
+		result.add(partial.result)
+		if partial.err != nil && err == nil {
+			err = partial.err
+		}
+	}
+
+	return result, err
+}
+
+type workerResult struct {
+	result Result
+	err    error
+}
+
+// checkEvery is how many games a worker plays between checks for cancellation.
+const checkEvery = 256
+
+func play(ctx context.Context, g game.Game, games int) (Result, error) {
+	result := newResult(g.PlayerCount())
+
+	for i := 0; i < games; i++ {
+		if i%checkEvery == 0 {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+		}
+
+		turns, _, err := g.Play()
+		if err != nil {
+			return result, err
+		}
+
+		players := g.PlayerCount()
+		result.Games++
+		result.Turns[len(turns)]++
+		result.Rounds[(len(turns)+players-1)/players]++
+		result.SeatWins[(len(turns)-1)%players]++
+		for _, turn := range turns {
+			result.Faces[turn.Face.ID]++
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,142 @@
+package sim
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/bmoller/cherry-o/game"
+)
+
+func TestHistogram(t *testing.T) {
+	h := Histogram{2: 1, 4: 2, 9: 1}
+
+	switch {
+	case h.Total() != 4:
+		t.Fatalf("expected total 4 but got %d", h.Total())
+	case h.Mean() != 4.75:
+		t.Fatalf("expected mean 4.75 but got %f", h.Mean())
+	case h.Percentile(50) != 4:
+		t.Fatalf("expected median 4 but got %d", h.Percentile(50))
+	case h.Percentile(100) != 9:
+		t.Fatalf("expected 100th percentile 9 but got %d", h.Percentile(100))
+	case h.Percentile(0) != 2:
+		t.Fatalf("expected 0th percentile 2 but got %d", h.Percentile(0))
+	}
+	if values := h.Values(); fmt.Sprint(values) != "[2 4 9]" {
+		t.Fatalf("expected sorted values but got %v", values)
+	}
+	if empty := (Histogram{}); empty.Mean() != 0 || empty.Percentile(50) != 0 {
+		t.Fatal("an empty histogram should report zeros")
+	}
+}
+
+func TestRunInvalid(t *testing.T) {
+	testCases := map[string]Config{
+		"no players": {Games: 10, Rules: game.StandardRules()},
+		"no games":   {Players: 2, Rules: game.StandardRules()},
+		"bad rules":  {Players: 2, Games: 10},
+	}
+
+	for name, cfg := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Run(context.Background(), cfg); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestRunTotals(t *testing.T) {
+	for players := 1; players <= 6; players++ {
+		t.Run(fmt.Sprintf("%d players", players), func(t *testing.T) {
+			cfg := Config{
+				Players: players,
+				Games:   1001,
+				Rules:   game.StandardRules(),
+				Seed:    int64(players),
+				Workers: 3,
+			}
+			result, err := Run(context.Background(), cfg)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			var wins, spins int
+			for _, count := range result.SeatWins {
+				wins += count
+			}
+			for _, count := range result.Faces {
+				spins += count
+			}
+			var turns int
+			for length, count := range result.Turns {
+				turns += length * count
+			}
+
+			switch {
+			case result.Games != cfg.Games:
+				t.Fatalf("expected %d games but got %d", cfg.Games, result.Games)
+			case result.Turns.Total() != cfg.Games || result.Rounds.Total() != cfg.Games:
+				t.Fatalf("every game should have one length; got %d turns and %d rounds", result.Turns.Total(), result.Rounds.Total())
+			case wins != cfg.Games:
+				t.Fatalf("every game should have one winner; got %d", wins)
+			case spins != turns:
+				t.Fatalf("expected %d spins but counted %d", turns, spins)
+			case result.Turns.Percentile(0) < 3:
+				t.Fatalf("no game can be won in fewer than 3 spins; got %d", result.Turns.Percentile(0))
+			}
+			for face := range result.Faces {
+				if result.FaceRate(face) <= 0 {
+					t.Fatalf("face %s has no frequency", face)
+				}
+			}
+		})
+	}
+}
+
+func TestRunReproducible(t *testing.T) {
+	cfg := Config{
+		Players: 4,
+		Games:   500,
+		Rules:   game.StandardRules(),
+		Seed:    99,
+		Workers: 4,
+	}
+
+	a, errA := Run(context.Background(), cfg)
+	b, errB := Run(context.Background(), cfg)
+	if errA != nil || errB != nil {
+		t.Fatalf("unexpected errors: %v, %v", errA, errB)
+	}
+	if fmt.Sprint(a) != fmt.Sprint(b) {
+		t.Fatal("the same seed and worker count should give the same results")
+	}
+}
+
+func TestRunCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := Run(ctx, Config{
+		Players: 2,
+		Games:   1000000,
+		Rules:   game.StandardRules(),
+	})
+	if err != context.Canceled {
+		t.Fatalf("expected %s but got %v", context.Canceled, err)
+	}
+	if result.Games != 0 {
+		t.Fatalf("no games should be played after cancellation; got %d", result.Games)
+	}
+}
+
+func TestRoster(t *testing.T) {
+	g, err := Roster(12, game.StandardRules())
+	if err != nil {
+		t.Fatalf("failed to seat 12 players: %s", err)
+	}
+	if g.PlayerCount() != 12 {
+		t.Fatalf("expected 12 players but got %d", g.PlayerCount())
+	}
+}
@@ -0,0 +1,65 @@
+package game
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	mathrand "math/rand"
+)
+
+// Spinner is the source of randomness for a Game; Spin returns a face index in the range [0, n).
+type Spinner interface {
+	Spin(n int) (int, error)
+}
+
+// CryptoSpinner draws spins from crypto/rand and is the default when a Game has no Spinner set.
+type CryptoSpinner struct{}
+
+func (CryptoSpinner) Spin(n int) (int, error) {
+	if n <= 0 {
+		return 0, errors.New("spinner needs at least one face")
+	}
+
+	spin, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate a random number: %s", err)
+	}
+
+	return int(spin.Int64()), nil
+}
+
+// SeededSpinner produces a deterministic sequence of spins from its seed; it is not safe for concurrent use.
+type SeededSpinner struct {
+	seed int64
+	rng  *mathrand.Rand
+}
+
+func NewSeededSpinner(seed int64) *SeededSpinner {
+	return &SeededSpinner{
+		seed: seed,
+		rng:  mathrand.New(mathrand.NewSource(seed)),
+	}
+}
+
+func (s *SeededSpinner) Seed() int64 {
+	return s.seed
+}
+
+func (s *SeededSpinner) Spin(n int) (int, error) {
+	if n <= 0 {
+		return 0, errors.New("spinner needs at least one face")
+	}
+
+	return s.rng.Intn(n), nil
+}
+
+// NewSeed returns a random seed suitable for NewSeededSpinner.
+func NewSeed() (int64, error) {
+	seed, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate a random seed: %s", err)
+	}
+
+	return seed.Int64(), nil
+}
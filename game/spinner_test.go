@@ -0,0 +1,53 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCryptoSpinnerRange(t *testing.T) {
+	for _, n := range []int{1, 2, 7, 100} {
+		t.Run(fmt.Sprintf("%d faces", n), func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				spin, err := CryptoSpinner{}.Spin(n)
+				switch {
+				case err != nil:
+					t.Fatal("failed to generate a random number; local entropy is likely low")
+				case spin < 0 || spin >= n:
+					t.Fatalf("spin %d is outside of [0, %d)", spin, n)
+				}
+			}
+		})
+	}
+}
+
+func TestSpinnerNoFaces(t *testing.T) {
+	for name, spinner := range map[string]Spinner{
+		"crypto": CryptoSpinner{},
+		"seeded": NewSeededSpinner(1),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := spinner.Spin(0); err == nil {
+				t.Fatal("shouldn't be able to spin without any faces")
+			}
+		})
+	}
+}
+
+func TestSeededSpinnerDeterministic(t *testing.T) {
+	for _, seed := range []int64{0, 1, 42, -7, 1 << 40} {
+		t.Run(fmt.Sprintf("seed %d", seed), func(t *testing.T) {
+			a, b := NewSeededSpinner(seed), NewSeededSpinner(seed)
+			if a.Seed() != seed {
+				t.Fatalf("expected seed %d but got %d", seed, a.Seed())
+			}
+			for i := 0; i < 1000; i++ {
+				spinA, _ := a.Spin(7)
+				spinB, _ := b.Spin(7)
+				if spinA != spinB {
+					t.Fatalf("spin %d differs: %d and %d", i, spinA, spinB)
+				}
+			}
+		})
+	}
+}
@@ -1,18 +1,110 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/bmoller/cherry-o/game"
 	"github.com/bmoller/cherry-o/ui"
 )
 
 func main() {
-	prog := tea.NewProgram(ui.New(), tea.WithAltScreen())
-	if err := prog.Start(); err != nil {
-		fmt.Printf("Boo-boo :( - %s\n", err)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "analyze":
+			exit(runAnalyze(os.Args[2:]))
+		case "replay":
+			exit(runReplay(os.Args[2:]))
+		case "simulate":
+			exit(runSimulate(os.Args[2:]))
+		}
+	}
+
+	exit(runPlay(os.Args[1:]))
+}
+
+/*
+exit stops the program, reporting err if there is one.
+*/
+func exit(err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Boo-boo :( - %s\n", err)
 		os.Exit(1)
 	}
+	os.Exit(0)
+}
+
+/*
+addRuleFlags registers the flags that describe a ruleset on flags.
+The returned function builds the ruleset once flags have been parsed.
+*/
+func addRuleFlags(flags *flag.FlagSet) func() (game.Ruleset, error) {
+	var (
+		maxPlayers  = flags.Int("max-players", game.MaxPlayers, "largest number of players allowed in a game")
+		overflow    = flags.String("overflow", game.Clamp.String(), "what happens past the target score: clamp, bounce, or overshoot")
+		shareColors = flags.Bool("share-colors", false, "allow more than one player to use the same color")
+		target      = flags.Int("target", game.WinningScore, "number of cherries needed to win")
+	)
+
+	return func() (game.Ruleset, error) {
+		var err error
+
+		rules := game.StandardRules()
+		rules.MaxPlayers = *maxPlayers
+		rules.ShareColors = *shareColors
+		rules.TargetScore = *target
+		if rules.Overflow, err = game.ParseOverflow(*overflow); err != nil {
+			return rules, err
+		}
+
+		return rules, rules.Validate()
+	}
+}
+
+// Describes the accessible flag shared by the commands that show the game.
+const accessibleUsage = "mark each player with a symbol and name their colors, for telling players apart without color (also on when NO_COLOR is set, or set \"accessible\" in settings.json in the user config directory)"
+
+// Describes the keys flag shared by the commands that show the game.
+var keysUsage = fmt.Sprintf("key bindings to start from: %s; keys.json in the user config directory can change any binding",
+	strings.Join(ui.KeyPresetNames(), " or "))
+
+// Describes the theme flag shared by the commands that show the game.
+var themeUsage = fmt.Sprintf("colors to draw with: %s, or the path of a theme file (default: theme.json in the user config directory, or ansi)",
+	strings.Join(ui.ThemeNames(), ", "))
+
+/*
+runPlay starts the interactive game.
+*/
+func runPlay(args []string) error {
+	flags := flag.NewFlagSet("cherry-o", flag.ExitOnError)
+	var (
+		accessible = flags.Bool("accessible", false, accessibleUsage)
+		ascii      = flags.Bool("ascii", false, "draw the board without emoji")
+		keys       = flags.String("keys", "", keysUsage)
+		record     = flags.String("record", "", "save a replay of each game played to this file")
+		theme      = flags.String("theme", "", themeUsage)
+	)
+	rules := addRuleFlags(flags)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: cherry-o [flags]\n       cherry-o analyze [flags]\n       cherry-o replay [flags] <file>\n       cherry-o simulate [flags]\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	opts := ui.Options{Accessible: *accessible, ASCII: *ascii, Keys: *keys, RecordPath: *record, Theme: *theme}
+	var err error
+	if opts.Rules, err = rules(); err != nil {
+		return err
+	}
+
+	m, err := ui.New(opts)
+	if err != nil {
+		return err
+	}
+
+	return tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion()).Start()
 }
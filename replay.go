@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/bmoller/cherry-o/game/replay"
+	"github.com/bmoller/cherry-o/ui"
+)
+
+/*
+runReplay handles the replay command, which checks a recorded game against its seed and then shows it.
+*/
+func runReplay(args []string) error {
+	flags := flag.NewFlagSet("cherry-o replay", flag.ExitOnError)
+	var (
+		accessible = flags.Bool("accessible", false, accessibleUsage)
+		ascii      = flags.Bool("ascii", false, "draw the board without emoji")
+		check      = flags.Bool("check", false, "only verify the replay; don't show it")
+		keys       = flags.String("keys", "", keysUsage)
+		theme      = flags.String("theme", "", themeUsage)
+	)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: cherry-o replay [flags] <file>\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("expected exactly one replay file")
+	}
+
+	recording, err := replay.Load(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if _, err = recording.Verify(); err != nil {
+		return fmt.Errorf("replay doesn't match its seed: %s", err)
+	}
+
+	winner, _ := recording.Winner()
+	fmt.Printf("Verified %d turns from seed %d; %s won.\n", len(recording.Turns), recording.Seed, winner)
+	if *check {
+		return nil
+	}
+
+	m, err := ui.New(ui.Options{Accessible: *accessible, ASCII: *ascii, Keys: *keys, Replay: &recording, Theme: *theme})
+	if err != nil {
+		return err
+	}
+
+	return tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion()).Start()
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/bmoller/cherry-o/game"
+	"github.com/bmoller/cherry-o/game/sim"
+)
+
+/*
+runSimulate handles the simulate command, which plays many headless games and reports on them.
+*/
+func runSimulate(args []string) error {
+	flags := flag.NewFlagSet("cherry-o simulate", flag.ExitOnError)
+	var (
+		format  = flags.String("format", "table", "output format: json, csv, or table")
+		games   = flags.Int("games", 100000, "number of games to play")
+		players = flags.Int("players", 4, "number of players in each game")
+		seed    = flags.Int64("seed", 0, "seed for the spinners; random when zero")
+		workers = flags.Int("workers", 0, "number of games to play at once; one per CPU when zero")
+	)
+	rules := addRuleFlags(flags)
+	flags.Parse(args)
+
+	var write func(io.Writer, sim.Result) error
+	switch *format {
+	case "json":
+		write = writeSimulationJSON
+	case "csv":
+		write = writeSimulationCSV
+	case "table":
+		write = writeSimulationTable
+	default:
+		return fmt.Errorf("unknown format %q; expected json, csv, or table", *format)
+	}
+
+	cfg := sim.Config{
+		Games:   *games,
+		Players: *players,
+		Seed:    *seed,
+		Workers: *workers,
+	}
+	var err error
+	if cfg.Rules, err = rules(); err != nil {
+		return err
+	}
+	if cfg.Seed == 0 {
+		if cfg.Seed, err = game.NewSeed(); err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	result, err := sim.Run(ctx, cfg)
+	if err != nil && result.Games == 0 {
+		return err
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Stopped early after %d games: %s\n", result.Games, err)
+	}
+
+	return write(os.Stdout, result)
+}
+
+/*
+simulationFaces returns the faces of result in a stable order, most frequent first.
+*/
+func simulationFaces(result sim.Result) []game.FaceID {
+	faces := make([]game.FaceID, 0, len(result.Faces))
+	for face := range result.Faces {
+		faces = append(faces, face)
+	}
+	sort.Slice(faces, func(i, j int) bool {
+		if result.Faces[faces[i]] != result.Faces[faces[j]] {
+			return result.Faces[faces[i]] > result.Faces[faces[j]]
+		}
+		return faces[i] < faces[j]
+	})
+
+	return faces
+}
+
+type simulationSummary struct {
+	Mean float64     `json:"mean"`
+	Min  int         `json:"min"`
+	P50  int         `json:"p50"`
+	P90  int         `json:"p90"`
+	P99  int         `json:"p99"`
+	Max  int         `json:"max"`
+	Dist map[int]int `json:"distribution"`
+}
+
+func summarize(h sim.Histogram) simulationSummary {
+	return simulationSummary{
+		Mean: h.Mean(),
+		Min:  h.Percentile(0),
+		P50:  h.Percentile(50),
+		P90:  h.Percentile(90),
+		P99:  h.Percentile(99),
+		Max:  h.Percentile(100),
+		Dist: h,
+	}
+}
+
+func writeSimulationJSON(w io.Writer, result sim.Result) error {
+	type seat struct {
+		Seat    int     `json:"seat"`
+		Wins    int     `json:"wins"`
+		WinRate float64 `json:"winRate"`
+	}
+	type face struct {
+		Face  game.FaceID `json:"face"`
+		Count int         `json:"count"`
+		Rate  float64     `json:"rate"`
+	}
+	output := struct {
+		Games   int               `json:"games"`
+		Players int               `json:"players"`
+		Seed    int64             `json:"seed"`
+		Turns   simulationSummary `json:"turns"`
+		Rounds  simulationSummary `json:"rounds"`
+		Seats   []seat            `json:"seats"`
+		Faces   []face            `json:"faces"`
+	}{
+		Games:   result.Games,
+		Players: result.Players,
+		Seed:    result.Seed,
+		Turns:   summarize(result.Turns),
+		Rounds:  summarize(result.Rounds),
+	}
+	for i, wins := range result.SeatWins {
+		output.Seats = append(output.Seats, seat{i + 1, wins, result.WinRate(i)})
+	}
+	for _, id := range simulationFaces(result) {
+		output.Faces = append(output.Faces, face{id, result.Faces[id], result.FaceRate(id)})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
+
+/*
+writeSimulationCSV writes one row per measurement as section, key, count, and fraction of the section's total.
+*/
+func writeSimulationCSV(w io.Writer, result sim.Result) error {
+	var (
+		out      = csv.NewWriter(w)
+		fraction = func(count, total int) string {
+			return strconv.FormatFloat(float64(count)/float64(total), 'f', 6, 64)
+		}
+	)
+
+	out.Write([]string{"section", "key", "count", "fraction"})
+	out.Write([]string{"summary", "games", strconv.Itoa(result.Games), ""})
+	out.Write([]string{"summary", "players", strconv.Itoa(result.Players), ""})
+	out.Write([]string{"summary", "seed", strconv.FormatInt(result.Seed, 10), ""})
+	for _, section := range []struct {
+		name string
+		h    sim.Histogram
+	}{
+		{"turns", result.Turns},
+		{"rounds", result.Rounds},
+	} {
+		for _, value := range section.h.Values() {
+			out.Write([]string{section.name, strconv.Itoa(value), strconv.Itoa(section.h[value]), fraction(section.h[value], result.Games)})
+		}
+	}
+	for i, wins := range result.SeatWins {
+		out.Write([]string{"seat", strconv.Itoa(i + 1), strconv.Itoa(wins), fraction(wins, result.Games)})
+	}
+	for _, id := range simulationFaces(result) {
+		out.Write([]string{"face", string(id), strconv.Itoa(result.Faces[id]), strconv.FormatFloat(result.FaceRate(id), 'f', 6, 64)})
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+func writeSimulationTable(w io.Writer, result sim.Result) error {
+	out := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(out, "Simulated %d games with %d players (seed %d)\n\n", result.Games, result.Players, result.Seed)
+
+	fmt.Fprintln(out, "Length\tMean\tMin\tMedian\t90%\t99%\tMax")
+	for _, row := range []struct {
+		name string
+		h    sim.Histogram
+	}{
+		{"Turns", result.Turns},
+		{"Rounds", result.Rounds},
+	} {
+		s := summarize(row.h)
+		fmt.Fprintf(out, "%s\t%.2f\t%d\t%d\t%d\t%d\t%d\n", row.name, s.Mean, s.Min, s.P50, s.P90, s.P99, s.Max)
+	}
+
+	fmt.Fprintln(out, "\nSeat\tWins\tWin rate")
+	for i, wins := range result.SeatWins {
+		fmt.Fprintf(out, "%d\t%d\t%.2f%%\n", i+1, wins, 100*result.WinRate(i))
+	}
+
+	fmt.Fprintln(out, "\nFace\tSpins\tRate")
+	for _, id := range simulationFaces(result) {
+		fmt.Fprintf(out, "%s\t%d\t%.2f%%\n", id, result.Faces[id], 100*result.FaceRate(id))
+	}
+
+	return out.Flush()
+}
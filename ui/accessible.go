@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/bmoller/cherry-o/game"
+)
+
+/*
+accessibleRequested reports whether the environment asks for output that doesn't rely on color.
+lipgloss already drops colors when NO_COLOR is set, so players need telling apart some other way.
+*/
+func accessibleRequested() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
+/*
+playerMarker returns the marker that sets player apart in m's accessible mode, followed by a space.
+Markers go by the order players joined rather than by color, since players sharing a color still need telling apart; once
+they run out they're given again with a number after them.
+Outside of accessible mode players are told apart by color alone, so it's empty.
+*/
+func playerMarker(m model, player game.Player) string {
+	if !m.accessible {
+		return ""
+	}
+
+	symbols := emojiSymbols
+	if m.ascii {
+		symbols = asciiSymbols
+	}
+	// IDs are handed out in the order players join, so the players with lower IDs joined first
+	var joined int
+	for _, other := range m.game.Players() {
+		if other.ID() < player.ID() {
+			joined++
+		}
+	}
+
+	marker := symbols.markers[joined%len(symbols.markers)]
+	if round := joined / len(symbols.markers); round > 0 {
+		marker += strconv.Itoa(round + 1)
+	}
+	return marker + " "
+}
+
+/*
+spokenName returns player's name as it appears in sentences, followed by their color in m's accessible mode.
+*/
+func spokenName(m model, player game.Player) string {
+	if !m.accessible {
+		return player.Name
+	}
+
+	return fmt.Sprintf("%s (%s)", player.Name, player.Color())
+}
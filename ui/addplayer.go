@@ -1,8 +1,10 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"strconv"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
@@ -15,6 +17,7 @@ import (
 type addPlayerKeyMap struct {
 	Cancel        key.Binding
 	NextColor     key.Binding
+	NextField     key.Binding
 	PreviousColor key.Binding
 	Submit        key.Binding
 }
@@ -25,7 +28,7 @@ func (k addPlayerKeyMap) ShortHelp() []key.Binding {
 
 func (k addPlayerKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.PreviousColor, k.NextColor},
+		{k.PreviousColor, k.NextColor, k.NextField},
 		{k.Submit, k.Cancel},
 	}
 }
@@ -39,6 +42,10 @@ var addPlayerKeyBinds = addPlayerKeyMap{
 		key.WithHelp("↓", "Next color"),
 		key.WithKeys("down"),
 	),
+	NextField: key.NewBinding(
+		key.WithHelp("tab", "Name/age"),
+		key.WithKeys("tab"),
+	),
 	PreviousColor: key.NewBinding(
 		key.WithHelp("↑", "Previous color"),
 		key.WithKeys("up"),
@@ -49,7 +56,9 @@ var addPlayerKeyBinds = addPlayerKeyMap{
 	),
 }
 
-type colorsDelegate struct{}
+type colorsDelegate struct {
+	theme Theme
+}
 
 func (c colorsDelegate) Height() int {
 	return 1
@@ -69,18 +78,7 @@ func (c colorsDelegate) Render(w io.Writer, m list.Model, index int, item list.I
 		return
 	}
 
-	var renderFunc func(string) string
-	switch color {
-	case game.Blue:
-		renderFunc = styleBlue.Render
-	case game.Green:
-		renderFunc = styleGreen.Render
-	case game.Red:
-		renderFunc = styleRed.Render
-	case game.Yellow:
-		renderFunc = styleYellow.Render
-	}
-	itemText := renderFunc(color.String())
+	itemText := c.theme.playerStyle(color).Render(color.String())
 	if index == m.Index() {
 		fmt.Fprint(w, " > ", itemText)
 	} else {
@@ -88,33 +86,150 @@ func (c colorsDelegate) Render(w io.Writer, m list.Model, index int, item list.I
 	}
 }
 
-func updateAddPlayerState(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+/*
+startPlayerForm readies the name and age fields and color list, filled in with player's name, age, and color.
+The zero Player starts an empty form for a new player; otherwise player's own color is offered along with those free.
+*/
+func startPlayerForm(m model, player game.Player) (model, tea.Cmd) {
 	var (
-		cmd tea.Cmd
+		cmds      [2]tea.Cmd
+		colorList []list.Item
+		selected  int
+	)
+
+	available := m.game.AvailableColors()
+	for _, color := range game.Colors() {
+		if color == player.Color() {
+			selected = len(colorList)
+		}
+		if available[color] || color == player.Color() {
+			colorList = append(colorList, color)
+		}
+	}
+	m.nameInput.SetValue(player.Name)
+	m.nameInput.CursorEnd()
+	m.ageInput.Reset()
+	if player.Age() > 0 {
+		m.ageInput.SetValue(strconv.Itoa(player.Age()))
+	}
+	m.ageInput.Blur()
+	cmds[0] = m.nameInput.Focus()
+	cmds[1] = m.colorList.SetItems(colorList)
+	m.colorList.Select(selected)
+
+	return m, tea.Batch(cmds[:]...)
+}
+
+/*
+colorsFree reports whether g has a color left for a new player.
+*/
+func colorsFree(g game.Game) bool {
+	for _, available := range g.AvailableColors() {
+		if available {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+resetPlayerForm empties the name and age fields once the form is done with.
+*/
+func resetPlayerForm(m model) model {
+	m.nameInput.Reset()
+	m.ageInput.Reset()
+	m.ageInput.Blur()
+	return m
+}
+
+/*
+savePlayerForm returns m's game with the player in the form added, or with the player with id changed to match the
+form if id isn't the zero ID.
+Nothing is changed unless the whole form is valid.
+*/
+func savePlayerForm(m model, id game.PlayerID) (game.Game, error) {
+	var (
+		age int
 		err error
+		g   = m.game
 	)
 
+	color, ok := m.colorList.SelectedItem().(game.Color)
+	if !ok {
+		return m.game, errors.New("pick a color for the player")
+	}
+	if value := m.ageInput.Value(); value != "" {
+		if age, err = strconv.Atoi(value); err != nil || age <= 0 {
+			return m.game, fmt.Errorf("%q is not an age; leave it empty if you'd rather not say", value)
+		}
+	}
+
+	if id == 0 {
+		if g, err = g.AddPlayer(m.nameInput.Value(), color); err != nil {
+			return m.game, err
+		}
+		players := g.Players()
+		id = players[len(players)-1].ID()
+	} else if g, err = g.UpdatePlayer(id, m.nameInput.Value(), color); err != nil {
+		return m.game, err
+	}
+	if g, err = g.SetPlayerAge(id, age); err != nil {
+		return m.game, err
+	}
+
+	return g, nil
+}
+
+/*
+updatePlayerForm handles the keys that fill in the form, which are the same whether adding or editing a player.
+*/
+func updatePlayerForm(msg tea.KeyMsg, m model) (model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch {
+	case key.Matches(msg, m.keys.addPlayer.NextColor):
+		m.colorList.CursorDown()
+	case key.Matches(msg, m.keys.addPlayer.PreviousColor):
+		m.colorList.CursorUp()
+	case key.Matches(msg, m.keys.addPlayer.NextField):
+		if m.ageInput.Focused() {
+			m.ageInput.Blur()
+			cmd = m.nameInput.Focus()
+		} else {
+			m.nameInput.Blur()
+			cmd = m.ageInput.Focus()
+		}
+	case m.ageInput.Focused():
+		m.ageInput, cmd = m.ageInput.Update(msg)
+	default:
+		// send everything else to the name field
+		m.nameInput, cmd = m.nameInput.Update(msg)
+	}
+
+	return m, cmd
+}
+
+func updateAddPlayerState(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch {
-		case key.Matches(msg, addPlayerKeyBinds.Cancel):
-			m.nameInput.Reset()
+		case key.Matches(msg, m.keys.addPlayer.Cancel):
+			m = resetPlayerForm(m)
 			m.state = mainState
-		case key.Matches(msg, addPlayerKeyBinds.Submit):
+		case key.Matches(msg, m.keys.addPlayer.Submit):
 			// all done; make the call to add a player
-			if m.game, err = m.game.AddPlayer(m.nameInput.Value(), m.colorList.SelectedItem().(game.Color)); err != nil {
+			if g, err := savePlayerForm(m, 0); err != nil {
 				m.state = errorState
 				m.err = err
 			} else {
+				m = changeRoster(m, g)
 				m.state = mainState
 			}
-			m.nameInput.Reset()
-		case key.Matches(msg, addPlayerKeyBinds.NextColor, addPlayerKeyBinds.PreviousColor):
-			// moving color selection up or down
-			m.colorList, cmd = m.colorList.Update(msg)
+			m = resetPlayerForm(m)
 		default:
-			// send everything else to the name field
-			m.nameInput, cmd = m.nameInput.Update(msg)
+			m, cmd = updatePlayerForm(msg, m)
 		}
 	}
 
@@ -123,27 +238,73 @@ func updateAddPlayerState(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 
 var (
 	addPlayerTitle = lipgloss.NewStyle().
-			Border(lipgloss.ThickBorder(), false, false, true).
-			BorderForeground(yellow).Render("Add Player")
+			Border(lipgloss.ThickBorder(), false, false, true)
 	styleAddPlayer = lipgloss.NewStyle().
 			Border(lipgloss.NormalBorder(), true).
 			Padding(1, 2)
 )
 
-func viewAddPlayerState(m model) string {
+/*
+renderAddPlayerBox draws the name and age inputs and color list in their box, titled for adding or editing as m's state is.
+It also returns the row of the box that the first color shown is on.
+*/
+func renderAddPlayerBox(m model) (string, int) {
+	heading := "Add Player"
+	if m.state == editPlayerState {
+		heading = "Edit Player"
+	}
+
+	var (
+		title     = addPlayerTitle.Copy().BorderForeground(m.theme.Accent.color()).Render(heading)
+		listTitle = m.colorList.Styles.TitleBar.Render(m.colorList.Styles.Title.Render(m.colorList.Title))
+	)
+
 	addPlayerContent := lipgloss.JoinVertical(
 		lipgloss.Center,
-		addPlayerTitle,
-		m.nameInput.View(),
+		title,
+		// the inputs line up with each other, whatever their placeholders
+		lipgloss.JoinVertical(lipgloss.Left, m.nameInput.View(), m.ageInput.View()),
 		"",
 		m.colorList.View(),
 	)
+	// below the border, padding, title, inputs and the line after them, and the list's own title
+	firstColor := 1 + 1 + lipgloss.Height(title) + lipgloss.Height(m.nameInput.View()) + lipgloss.Height(m.ageInput.View()) + 1 +
+		lipgloss.Height(listTitle)
+
+	return styleAddPlayer.Render(addPlayerContent), firstColor
+}
+
+/*
+colorAt returns the index of the color listed at x and y of the main pane's content, if any.
+*/
+func colorAt(m model, x int, y int) (int, bool) {
+	box, firstColor := renderAddPlayerBox(m)
+
+	// the box is centered in the main pane, as placed by viewAddPlayerState and viewEditPlayerState
+	var (
+		left = (m.layout.mainWidth - lipgloss.Width(box)) / 2
+		top  = (m.layout.mainHeight - lipgloss.Height(box)) / 2
+		row  = y - top - firstColor
+	)
+	if x < left || x >= left+lipgloss.Width(box) || row < 0 || row >= m.colorList.Paginator.PerPage {
+		return 0, false
+	}
+
+	index := m.colorList.Paginator.Page*m.colorList.Paginator.PerPage + row
+	if index >= len(m.colorList.Items()) {
+		return 0, false
+	}
+	return index, true
+}
+
+func viewAddPlayerState(m model) string {
+	box, _ := renderAddPlayerBox(m)
 
-	addPlayerPlacement := lipgloss.Place(mainPane.GetWidth(), mainPane.GetHeight(),
+	addPlayerPlacement := lipgloss.Place(m.layout.mainWidth, m.layout.mainHeight,
 		lipgloss.Center, lipgloss.Center,
-		styleAddPlayer.Render(addPlayerContent),
+		box,
 		lipgloss.WithWhitespaceChars("-"),
-		lipgloss.WithWhitespaceForeground(yellow))
+		lipgloss.WithWhitespaceForeground(m.theme.Accent.color()))
 
-	return assembleView(renderPlayers(m, -1), renderHelpContent(m, addPlayerKeyBinds), addPlayerPlacement)
+	return m.layout.assemble(m.theme, renderPlayers(m, 0), renderHelpContent(m, m.keys.addPlayer), addPlayerPlacement)
 }
@@ -0,0 +1,289 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/bmoller/cherry-o/game"
+)
+
+const (
+	// Width given to each player's drawing on the board, including the space between drawings.
+	boardColumnWidth int = 18
+	// Number of cells in a bucket's fill gauge.
+	bucketCells int = 10
+	// Most cherries drawn on their way between tree and bucket before the rest are summarized.
+	maxMovingCherries int = 5
+	// Number of cherry spots on each row of a tree.
+	treeRowSpots int = 5
+)
+
+/*
+boardSymbols holds the pieces the board is drawn with, so that it can be drawn without emoji where they aren't supported.
+*/
+type boardSymbols struct {
+	// A cherry still on the tree or on its way between tree and bucket.
+	cherry string
+	// A spot on the tree whose cherry has been picked; the same width as cherry.
+	empty string
+	// Goes between spots on the tree.
+	gap string
+	// One cell of a bucket's fill gauge or a progress bar, and an empty cell of a progress bar.
+	fill  string
+	track string
+	// Marks cherries going into the bucket and back to the tree.
+	down string
+	up   string
+	// Marks the winner's name.
+	crown string
+	// Sets players apart in accessible mode, for those who can't tell the colors apart; one for each of the first twelve
+	// players to join, as players sharing a color need telling apart too.
+	markers []string
+}
+
+var (
+	emojiSymbols = boardSymbols{
+		cherry:  "🍒",
+		empty:   "· ",
+		fill:    "█",
+		track:   "░",
+		down:    "↓",
+		up:      "↑",
+		crown:   "👑 ",
+		markers: []string{"●", "▲", "■", "★", "◆", "♥", "♣", "♠", "○", "△", "□", "☆"},
+	}
+	asciiSymbols = boardSymbols{
+		cherry:  "o",
+		empty:   ".",
+		gap:     " ",
+		fill:    "#",
+		track:   "-",
+		down:    "v",
+		up:      "^",
+		crown:   "* ",
+		markers: []string{"A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L"},
+	}
+)
+
+/*
+emojiSupported guesses from the environment whether the terminal can draw emoji.
+The Linux console can't, and neither can a terminal that isn't using a UTF-8 locale.
+*/
+func emojiSupported() bool {
+	if os.Getenv("TERM") == "linux" {
+		return false
+	}
+
+	// the first of these that is set decides the character set, as with setlocale
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := strings.ToUpper(os.Getenv(name)); value != "" {
+			return strings.Contains(value, "UTF-8") || strings.Contains(value, "UTF8")
+		}
+	}
+
+	return false
+}
+
+/*
+movingCherries works out how many cherries the most recent turn of m's playback is still moving, and for whom.
+Cherries are picked into the bucket during the first half of the following spin, and put back on the tree the same way when lost.
+The count is negative for cherries going back to the tree; the zero ID means nothing is moving.
+*/
+func movingCherries(m model) (game.PlayerID, int) {
+	if m.state != playbackState || m.playback.shown == 0 || m.playback.frame >= spinFrames/2 {
+		return 0, 0
+	}
+
+	var (
+		revealed = m.playback.turns[:m.playback.shown]
+		last     = revealed[len(revealed)-1]
+		before   = 0
+	)
+	for i := len(revealed) - 2; i >= 0; i-- {
+		if revealed[i].Player.ID() == last.Player.ID() {
+			before = revealed[i].Player.Cherries()
+			break
+		}
+	}
+
+	return last.Player.ID(), last.Player.Cherries() - before
+}
+
+/*
+renderTree draws player's tree above their bucket, in their color from theme.
+The tree has a spot for each of the target cherries, and between them are any cherries that are moving.
+marker goes before the player's name, to set them apart in accessible mode.
+A tree with more rows of spots than maxRows draws only the first of them, and counts what's left on the tree in the last.
+*/
+func renderTree(player game.Player, marker string, cherries int, moving int, target int, won bool, maxRows int, symbols boardSymbols, theme Theme) string {
+	// moving cherries have left one place but not yet arrived at the other
+	tree, bucket := target-cherries, cherries
+	if moving > 0 {
+		bucket -= moving
+	} else {
+		tree += moving
+	}
+	if tree < 0 {
+		tree = 0
+	}
+	if bucket < 0 {
+		bucket = 0
+	}
+
+	var (
+		lines []string
+		name  = marker + truncateName(player.Name, boardColumnWidth-6-lipgloss.Width(marker))
+		rows  []string
+		spots []string
+	)
+	if won {
+		name = symbols.crown + name
+	}
+	lines = append(lines, fmt.Sprintf("%s %d", name, cherries))
+
+	for i := 0; i < target; i++ {
+		if i < tree {
+			spots = append(spots, symbols.cherry)
+		} else {
+			spots = append(spots, symbols.empty)
+		}
+		if len(spots) == treeRowSpots || i == target-1 {
+			rows = append(rows, strings.Join(spots, symbols.gap))
+			spots = nil
+		}
+	}
+	canopyWidth := lipgloss.Width(rows[0])
+	if maxRows < 1 {
+		maxRows = 1
+	}
+	if len(rows) > maxRows {
+		// cherries are drawn first, so those left over are after every spot that's shown
+		shown := (maxRows - 1) * treeRowSpots
+		summary := symbols.cherry + fmt.Sprintf("+%d", tree-shown)
+		if tree <= shown {
+			summary = strings.TrimSpace(symbols.empty) + fmt.Sprintf("+%d", target-shown)
+		}
+		rows = append(rows[:maxRows-1], summary)
+	}
+	lines = append(lines, " ."+strings.Repeat("-", canopyWidth)+". ")
+	for _, row := range rows {
+		pad := canopyWidth - lipgloss.Width(row)
+		if pad < 0 {
+			pad = 0
+		}
+		lines = append(lines, "( "+row+strings.Repeat(" ", pad)+" )")
+	}
+	lines = append(lines, " '"+strings.Repeat("-", canopyWidth)+"' ")
+
+	if moving == 0 {
+		lines = append(lines, "| |")
+	} else {
+		arrow, count := symbols.down, moving
+		if moving < 0 {
+			arrow, count = symbols.up, -moving
+		}
+		shown := count
+		if shown > maxMovingCherries {
+			shown = maxMovingCherries
+		}
+		flight := strings.Repeat(symbols.cherry, shown)
+		if count > shown {
+			flight += fmt.Sprintf("+%d", count-shown)
+		}
+		lines = append(lines, arrow+" "+flight+" "+arrow)
+	}
+
+	filled := bucket * bucketCells / target
+	if filled > bucketCells {
+		filled = bucketCells
+	} else if filled == 0 && bucket > 0 {
+		filled = 1
+	}
+	lines = append(lines,
+		"|"+strings.Repeat(symbols.fill, filled)+strings.Repeat(" ", bucketCells-filled)+"|",
+		`\`+strings.Repeat("_", bucketCells)+`/`,
+	)
+
+	return theme.playerStyle(player.Color()).
+		Width(boardColumnWidth).
+		Align(lipgloss.Center).
+		Render(lipgloss.JoinVertical(lipgloss.Center, lines...))
+}
+
+/*
+renderBoard draws every player's tree and bucket as of the turns shown so far, below caption.
+Trees are laid out in rows across the main pane; players that don't fit are counted at the bottom.
+*/
+func renderBoard(m model, caption string) string {
+	var (
+		cherries         = m.playback.standings()
+		movingID, moving = movingCherries(m)
+		players          = m.game.Players()
+		symbols          = emojiSymbols
+		target           = m.game.Rules().TargetScore
+		trees            []string
+		// a tree's rows of spots go between its name, canopy, trunk, and bucket, below the caption and any count
+		treeRows = m.layout.mainHeight - 3 - 6
+	)
+	if m.ascii {
+		symbols = asciiSymbols
+	}
+
+	for _, player := range players {
+		var playerMoving int
+		if player.ID() == movingID {
+			playerMoving = moving
+		}
+		won := m.winner.ID() != 0 && m.winner.ID() == player.ID()
+		trees = append(trees, renderTree(player, playerMarker(m, player), cherries[player.ID()], playerMoving, target, won, treeRows, symbols, m.theme))
+	}
+
+	var (
+		columns = m.layout.mainWidth / boardColumnWidth
+		hidden  int
+		rows    = []string{caption, ""}
+	)
+	if columns < 1 {
+		columns = 1
+	}
+	if len(trees) > 0 {
+		// leave room for the caption and a count of anyone left out
+		fit := (m.layout.mainHeight - 3) / lipgloss.Height(trees[0]) * columns
+		if fit < columns {
+			fit = columns
+		}
+		if fit < len(trees) {
+			hidden = len(trees) - fit
+			trees = trees[:fit]
+		}
+	}
+	for start := 0; start < len(trees); start += columns {
+		end := start + columns
+		if end > len(trees) {
+			end = len(trees)
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, trees[start:end]...))
+	}
+	if hidden > 0 {
+		rows = append(rows, fmt.Sprintf("…and %d more", hidden))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Center, rows...)
+}
+
+/*
+renderMainContent returns what goes in the main pane of states that show the game: the board when it's toggled on, or the turn list.
+caption is shown above the board in place of the latest line of the turn list.
+*/
+func renderMainContent(m model, caption string) string {
+	if !m.showBoard {
+		return m.turnView.View()
+	}
+
+	return lipgloss.Place(m.layout.mainWidth, m.layout.mainHeight,
+		lipgloss.Center, lipgloss.Center,
+		renderBoard(m, caption))
+}
@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Name of the directory within the user's config directory that holds the settings files.
+const configDirName = "cherry-o"
+
+/*
+configPath returns where the settings file name lives in the user's config directory.
+*/
+func configPath(name string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, configDirName, name), nil
+}
+
+/*
+readConfig reads the settings file name from the user's config directory, returning its contents and path.
+A missing file isn't an error; the path is empty if there is no such file.
+*/
+func readConfig(name string) ([]byte, string, error) {
+	path, err := configPath(name)
+	if err != nil {
+		// without a config directory there can't be a settings file
+		return nil, "", nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, "", nil
+	}
+
+	return data, path, err
+}
+
+// Name of the file in the user's config directory that holds general settings.
+const settingsFile = "settings.json"
+
+/*
+settings are the preferences kept in the user's config directory, for those who'd rather not pass flags every time.
+*/
+type settings struct {
+	// Turns on accessible mode, as the accessible flag does.
+	Accessible bool `json:"accessible"`
+}
+
+/*
+loadSettings reads the settings file from the user's config directory; without one, every setting is left at its default.
+*/
+func loadSettings() (settings, error) {
+	var s settings
+
+	data, path, err := readConfig(settingsFile)
+	if err != nil || path == "" {
+		return s, err
+	}
+	if err = json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("invalid settings in %s: %s", path, err)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+/*
+updateEditPlayerState changes the selected player to match the form, going back to the roster when done.
+The form works just as when adding a player, so it shares those key bindings.
+*/
+func updateEditPlayerState(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.addPlayer.Cancel):
+			// back to the roster, with the same player still selected
+			m = resetPlayerForm(m)
+			m.state = rosterState
+		case key.Matches(msg, m.keys.addPlayer.Submit):
+			if g, err := savePlayerForm(m, m.selectedPlayer); err != nil {
+				m.state = errorState
+				m.err = err
+				m.selectedPlayer = 0
+			} else {
+				m = changeRoster(m, g)
+				m.state = rosterState
+			}
+			m = resetPlayerForm(m)
+		default:
+			m, cmd = updatePlayerForm(msg, m)
+		}
+	}
+
+	return m, cmd
+}
+
+func viewEditPlayerState(m model) string {
+	box, _ := renderAddPlayerBox(m)
+
+	editPlayerPlacement := lipgloss.Place(m.layout.mainWidth, m.layout.mainHeight,
+		lipgloss.Center, lipgloss.Center,
+		box,
+		lipgloss.WithWhitespaceChars("-"),
+		lipgloss.WithWhitespaceForeground(m.theme.Accent.color()))
+
+	return m.layout.assemble(m.theme, renderPlayers(m, m.selectedPlayer), renderHelpContent(m, m.keys.addPlayer), editPlayerPlacement)
+}
@@ -33,7 +33,7 @@ func updateErrorState(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch {
-		case key.Matches(msg, errorKeyBinds.Dismiss):
+		case key.Matches(msg, m.keys.error.Dismiss):
 			m.err = nil
 			m.state = mainState
 		}
@@ -44,16 +44,14 @@ func updateErrorState(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 
 var styleErrorMsg = lipgloss.NewStyle().
 	Border(lipgloss.RoundedBorder(), true).
-	BorderForeground(red).
-	Foreground(red).
 	Padding(1, 2)
 
 func viewErrorState(m model) string {
-	errorContent := lipgloss.Place(mainPane.GetWidth(), mainPane.GetHeight(),
+	errorContent := lipgloss.Place(m.layout.mainWidth, m.layout.mainHeight,
 		lipgloss.Center, lipgloss.Center,
-		styleErrorMsg.Render(m.err.Error()),
+		styleErrorMsg.Copy().BorderForeground(m.theme.Error.color()).Foreground(m.theme.Error.color()).Render(m.err.Error()),
 		lipgloss.WithWhitespaceChars("-"),
-		lipgloss.WithWhitespaceForeground(yellow))
+		lipgloss.WithWhitespaceForeground(m.theme.Accent.color()))
 
-	return assembleView(renderPlayers(m, -1), renderHelpContent(m, errorKeyBinds), errorContent)
+	return m.layout.assemble(m.theme, renderPlayers(m, 0), renderHelpContent(m, m.keys.error), errorContent)
 }
@@ -0,0 +1,206 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/bmoller/cherry-o/game"
+)
+
+// Most changes that can be undone; the oldest are forgotten beyond this.
+const historyLimit int = 100
+
+/*
+historyKeyMap holds the keys that undo and redo, shared by every state that keeps history.
+*/
+type historyKeyMap struct {
+	Redo key.Binding
+	Undo key.Binding
+}
+
+var historyKeyBinds = historyKeyMap{
+	Redo: key.NewBinding(
+		key.WithKeys("ctrl+r"),
+		key.WithHelp("^r", "Redo"),
+	),
+	Undo: key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "Undo"),
+	),
+}
+
+/*
+combined returns a single binding for both keys in k, so that undoing and redoing take one row of the help pane.
+*/
+func (k historyKeyMap) combined(desc string) key.Binding {
+	return key.NewBinding(
+		key.WithKeys(append(k.Undo.Keys(), k.Redo.Keys()...)...),
+		key.WithHelp(k.Undo.Help().Key+"/"+k.Redo.Help().Key, desc),
+	)
+}
+
+/*
+snapshot is everything that undo and redo put back: the roster, and any game being scored along with its turns.
+*/
+type snapshot struct {
+	game     game.Game
+	match    game.Game
+	playback playback
+	// Either scoreKeeperState, to carry on scoring match, or mainState for anything else.
+	state  appState
+	winner game.Player
+}
+
+/*
+history holds the snapshots to go back to, most recent last, and those undone that can be gone forward to again.
+*/
+type history struct {
+	undo []snapshot
+	redo []snapshot
+}
+
+/*
+takeSnapshot captures what m's history needs to put m back as it is now.
+*/
+func takeSnapshot(m model) snapshot {
+	s := snapshot{
+		game:     m.game,
+		match:    m.match,
+		playback: m.playback,
+		state:    mainState,
+		winner:   m.winner,
+	}
+	if m.state == scoreKeeperState {
+		s.state = scoreKeeperState
+	}
+
+	return s
+}
+
+/*
+pushSnapshot returns stack with s on top, dropping the oldest snapshot if it's full.
+*/
+func pushSnapshot(stack []snapshot, s snapshot) []snapshot {
+	if len(stack) >= historyLimit {
+		stack = stack[len(stack)-historyLimit+1:]
+	}
+	// never append into an array that an earlier model might share
+	return append(stack[:len(stack):len(stack)], s)
+}
+
+/*
+remember adds before, taken just ahead of a change to m, to m's history.
+Anything undone is then forgotten, as the change takes its place.
+*/
+func remember(m model, before snapshot) model {
+	m.history.undo = pushSnapshot(m.history.undo, before)
+	m.history.redo = nil
+
+	return m
+}
+
+/*
+changeRoster replaces m's game with g, remembering the roster it had so that the change can be undone.
+A g whose roster is the same as m's, such as from seating players in the order they already sit, changes nothing.
+*/
+func changeRoster(m model, g game.Game) model {
+	if sameRoster(m.game.Players(), g.Players()) {
+		return m
+	}
+
+	before := takeSnapshot(m)
+	m.game = g
+	m = clearPlayback(m)
+
+	return remember(m, before)
+}
+
+/*
+sameRoster reports whether a and b have the same players in the same seats.
+*/
+func sameRoster(a, b []game.Player) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*
+forgetScoring drops everything from m's history that would carry on scoring a game, such as once that game is abandoned.
+*/
+func forgetScoring(m model) model {
+	var undo []snapshot
+	for _, s := range m.history.undo {
+		if s.state != scoreKeeperState {
+			undo = append(undo, s)
+		}
+	}
+	m.history.undo = undo
+	m.history.redo = nil
+
+	return m
+}
+
+/*
+restore puts s back into m.
+The roster is shown in whatever state m is in, while a game being scored is always shown in the scoreKeeperState.
+*/
+func restore(m model, s snapshot) model {
+	generation := m.playback.generation
+	m.game = s.game
+	m.match = s.match
+	m.playback = s.playback
+	m.playback.generation = generation
+	m.winner = s.winner
+	if s.state == scoreKeeperState || m.state == scoreKeeperState {
+		m.state = s.state
+	}
+
+	if m.state == rosterState {
+		if m = moveSelection(m, 0); m.game.PlayerCount() == 0 {
+			m.state = mainState
+		}
+	}
+	m = refreshMatch(m)
+	m.turnView.GotoBottom()
+
+	return m
+}
+
+/*
+stepHistory undoes or redoes the most recent change in m's history, as msg asks.
+While scoring a game only its spins can be undone, so that undoing never leaves the game.
+*/
+func stepHistory(m model, msg tea.KeyMsg) model {
+	switch {
+	case key.Matches(msg, m.keys.history.Undo):
+		count := len(m.history.undo)
+		if count == 0 {
+			return m
+		}
+		previous := m.history.undo[count-1]
+		if m.state == scoreKeeperState && previous.state != scoreKeeperState {
+			return m
+		}
+		m.history.redo = pushSnapshot(m.history.redo, takeSnapshot(m))
+		m.history.undo = m.history.undo[:count-1]
+		return restore(m, previous)
+	case key.Matches(msg, m.keys.history.Redo):
+		count := len(m.history.redo)
+		if count == 0 {
+			return m
+		}
+		next := m.history.redo[count-1]
+		m.history.undo = pushSnapshot(m.history.undo, takeSnapshot(m))
+		m.history.redo = m.history.redo[:count-1]
+		return restore(m, next)
+	}
+
+	return m
+}
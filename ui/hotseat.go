@@ -0,0 +1,191 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/bmoller/cherry-o/game"
+	"github.com/bmoller/cherry-o/game/replay"
+)
+
+type hotSeatKeyMap struct {
+	// The undo and redo keys, bound only to say in the help why they do nothing here.
+	// Every spin is drawn from the game's seed, so a spin taken back and spun again would come out differently than when
+	// the seed is replayed, and the saved game could no longer be checked against it.
+	History     key.Binding
+	Quit        key.Binding
+	ScrollTurns key.Binding
+	Spin        key.Binding
+	ToggleBoard key.Binding
+}
+
+func (k hotSeatKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Spin, k.Quit}
+}
+
+func (k hotSeatKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Spin, k.Quit, k.ToggleBoard, k.History},
+		{k.ScrollTurns},
+	}
+}
+
+var hotSeatKeyBinds = hotSeatKeyMap{
+	History: historyKeyBinds.combined("No undo; spins are seeded"),
+	Quit: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "Stop game"),
+	),
+	ScrollTurns: turnKeyBinds.combined("Scroll turns"),
+	Spin: key.NewBinding(
+		key.WithKeys(" ", "enter"),
+		key.WithHelp("space", "Spin"),
+	),
+	ToggleBoard: key.NewBinding(
+		key.WithKeys("b"),
+		key.WithHelp("b", "Board view"),
+	),
+}
+
+var stylePrompt = lipgloss.NewStyle().Bold(true)
+
+/*
+startHotSeat begins a game of m's roster in which each player spins for themselves.
+The spinner is seeded so that the finished game can be saved as a replay.
+*/
+func startHotSeat(m model) (model, error) {
+	if m.game.PlayerCount() == 0 {
+		return m, errors.New("add a player before playing")
+	}
+
+	seed, err := game.NewSeed()
+	if err != nil {
+		return m, err
+	}
+
+	m = forgetScoring(m)
+	m.match = m.game.WithSpinner(game.NewSeededSpinner(seed))
+	m.matchSeed = seed
+	m.playback = playback{generation: m.playback.generation}
+	m.winner = game.Player{}
+	m.state = hotSeatState
+	m = refreshMatch(m)
+	m.turnView.GotoTop()
+
+	return m, nil
+}
+
+/*
+spinHotSeat takes the current player's turn.
+When the turn wins the game m returns to the mainState, and the command saves the game if m is recording.
+*/
+func spinHotSeat(m model) (model, tea.Cmd) {
+	var (
+		cmd  tea.Cmd
+		err  error
+		over bool
+		turn game.Turn
+	)
+
+	if m.match, turn, over, err = m.match.NextTurn(); err != nil {
+		m.err = err
+		m.state = errorState
+		return m, nil
+	}
+	m.playback.turns = append(m.playback.turns, turn)
+	m.playback.shown = len(m.playback.turns)
+
+	if over {
+		m.winner, _ = m.match.Winner()
+		m.state = mainState
+		if m.recordPath != "" {
+			cmd = saveHotSeat(m.game, m.matchSeed, m.recordPath)
+		}
+	}
+	m = refreshMatch(m)
+	m.turnView.GotoBottom()
+
+	return m, cmd
+}
+
+/*
+saveHotSeat returns a command that records the game played from setup with seed and saves it to path.
+Replaying the seed reproduces the turns that were spun by hand.
+*/
+func saveHotSeat(setup game.Game, seed int64, path string) tea.Cmd {
+	return func() tea.Msg {
+		recording, err := replay.Record(setup, seed)
+		if err != nil {
+			return errMsg{fmt.Errorf("failed to record game: %s", err)}
+		}
+		return saveReplay(recording, path)()
+	}
+}
+
+/*
+hotSeatPrompt asks the player whose turn it is to spin, or returns an empty string when no hot-seat game is on.
+*/
+func hotSeatPrompt(m model) string {
+	player, ok := m.match.CurrentPlayer()
+	if !ok || m.state != hotSeatState {
+		return ""
+	}
+
+	return m.theme.playerStyle(player.Color()).Inherit(stylePrompt).Render(fmt.Sprintf("%s, press %s to spin!", spokenName(m, player), m.keys.hotSeat.Spin.Help().Key))
+}
+
+/*
+refreshMatch renders the turns of m's match so far, followed by a prompt for the next player while a hot-seat game is on.
+*/
+func refreshMatch(m model) model {
+	var (
+		width   = m.turnView.Width - m.turnView.Style.GetHorizontalFrameSize()
+		content = renderTurns(m, m.playback.turns, width)
+	)
+
+	if prompt := hotSeatPrompt(m); prompt != "" {
+		content += lipgloss.NewStyle().Width(width).Render(prompt)
+	}
+	m.turnView.SetContent(content)
+
+	return m
+}
+
+func updateHotSeatState(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.hotSeat.Spin):
+			m, cmd = spinHotSeat(m)
+		case key.Matches(msg, m.keys.hotSeat.ToggleBoard):
+			m.showBoard = !m.showBoard
+		case key.Matches(msg, m.keys.hotSeat.Quit):
+			m.match = game.Game{}
+			m.state = mainState
+			m = refreshMatch(m)
+		case key.Matches(msg, m.keys.hotSeat.ScrollTurns):
+			m = scrollTurns(m, msg)
+		case key.Matches(msg, m.keys.hotSeat.History):
+			// spins can't be taken back; see hotSeatKeyMap
+		}
+	default:
+		m.turnView, cmd = m.turnView.Update(msg)
+	}
+
+	return m, cmd
+}
+
+func viewHotSeatState(m model) string {
+	var current game.PlayerID
+	if player, ok := m.match.CurrentPlayer(); ok {
+		current = player.ID()
+	}
+
+	return m.layout.assemble(m.theme, renderPlayers(m, current), renderHelpContent(m, m.keys.hotSeat), renderMainContent(m, hotSeatPrompt(m)))
+}
@@ -0,0 +1,438 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Name of the file in the user's config directory that holds custom key bindings.
+const keysFile = "keys.json"
+
+/*
+keyMaps holds the effective key bindings of every state, starting from the defaults and changed by any preset or keys file.
+*/
+type keyMaps struct {
+	addPlayer addPlayerKeyMap
+	error     errorKeyMap
+	// Undoing and redoing, which the main, roster, and score keeper states share.
+	history     historyKeyMap
+	hotSeat     hotSeatKeyMap
+	main        mainKeyMap
+	playback    playbackKeyMap
+	roster      rosterKeyMap
+	scoreKeeper scoreKeeperKeyMap
+	// Scrolling the turn list, which the main, playback, and hot-seat states share.
+	turns turnKeyMap
+}
+
+/*
+keyOverrides replaces the keys of bindings, by section and then by binding name; both are named as in keyMaps.sections.
+*/
+type keyOverrides map[string]map[string][]string
+
+/*
+keyPreset is a named set of overrides that can be chosen as a starting point.
+*/
+type keyPreset struct {
+	name      string
+	overrides keyOverrides
+}
+
+var keyPresets = []keyPreset{
+	{name: "default"},
+	{
+		name: "vim",
+		overrides: keyOverrides{
+			// k scrolls up, so keeping score moves to K
+			"main": {
+				"keepScore": {"K"},
+			},
+			"roster": {
+				"moveDown":       {"J", "shift+down"},
+				"moveUp":         {"K", "shift+up"},
+				"nextPlayer":     {"j", "down"},
+				"previousPlayer": {"k", "up"},
+			},
+			"scoreKeeper": {
+				"nextFace":     {"j", "down"},
+				"previousFace": {"k", "up"},
+			},
+			"turns": {
+				"bottom":       {"G", "end"},
+				"down":         {"j", "down"},
+				"halfPageDown": {"ctrl+d", "pgdown"},
+				"halfPageUp":   {"ctrl+u", "pgup"},
+				"top":          {"g", "home"},
+				"up":           {"k", "up"},
+			},
+		},
+	},
+}
+
+// Sections of keys that several states share, and those states, which must not bind the keys to anything else.
+var sharedSections = map[string]map[string]bool{
+	"history": {"hotSeat": true, "main": true, "roster": true, "scoreKeeper": true},
+	"turns":   {"hotSeat": true, "main": true, "playback": true},
+}
+
+/*
+KeyPresetNames lists the names of the built-in key binding presets.
+*/
+func KeyPresetNames() []string {
+	names := make([]string, len(keyPresets))
+	for i, preset := range keyPresets {
+		names[i] = preset.name
+	}
+	return names
+}
+
+/*
+defaultKeyMaps returns the key bindings every state starts with.
+*/
+func defaultKeyMaps() keyMaps {
+	return keyMaps{
+		addPlayer:   addPlayerKeyBinds,
+		error:       errorKeyBinds,
+		history:     historyKeyBinds,
+		hotSeat:     hotSeatKeyBinds,
+		main:        mainKeyBinds,
+		playback:    playbackKeyBinds,
+		roster:      rosterKeyBinds,
+		scoreKeeper: scoreKeeperKeyBinds,
+		turns:       turnKeyBinds,
+	}
+}
+
+/*
+keySection is the bindings of one state that can be changed, by name.
+*/
+type keySection struct {
+	name     string
+	bindings map[string]*key.Binding
+}
+
+/*
+sections names every binding that can be changed, grouped by the state it belongs to and ordered by name.
+The combined turn scrolling and history bindings aren't listed; they follow the turns and history sections.
+*/
+func (k *keyMaps) sections() []keySection {
+	return []keySection{
+		{"addPlayer", map[string]*key.Binding{
+			"cancel":        &k.addPlayer.Cancel,
+			"nextColor":     &k.addPlayer.NextColor,
+			"nextField":     &k.addPlayer.NextField,
+			"previousColor": &k.addPlayer.PreviousColor,
+			"submit":        &k.addPlayer.Submit,
+		}},
+		{"error", map[string]*key.Binding{
+			"dismiss": &k.error.Dismiss,
+		}},
+		{"history", map[string]*key.Binding{
+			"redo": &k.history.Redo,
+			"undo": &k.history.Undo,
+		}},
+		{"hotSeat", map[string]*key.Binding{
+			"quit":        &k.hotSeat.Quit,
+			"spin":        &k.hotSeat.Spin,
+			"toggleBoard": &k.hotSeat.ToggleBoard,
+		}},
+		{"main", map[string]*key.Binding{
+			"addPlayer":         &k.main.AddPlayer,
+			"keepScore":         &k.main.KeepScore,
+			"play":              &k.main.Play,
+			"quit":              &k.main.Quit,
+			"roster":            &k.main.Roster,
+			"scrollPlayersDown": &k.main.ScrollPlayersDown,
+			"scrollPlayersUp":   &k.main.ScrollPlayersUp,
+			"simulate":          &k.main.Simulate,
+			"toggleBoard":       &k.main.ToggleBoard,
+		}},
+		{"playback", map[string]*key.Binding{
+			"faster":      &k.playback.Faster,
+			"pause":       &k.playback.Pause,
+			"skip":        &k.playback.Skip,
+			"slower":      &k.playback.Slower,
+			"step":        &k.playback.Step,
+			"toggleBoard": &k.playback.ToggleBoard,
+		}},
+		{"roster", map[string]*key.Binding{
+			"done":           &k.roster.Done,
+			"edit":           &k.roster.Edit,
+			"moveDown":       &k.roster.MoveDown,
+			"moveUp":         &k.roster.MoveUp,
+			"nextPlayer":     &k.roster.NextPlayer,
+			"previousPlayer": &k.roster.PreviousPlayer,
+			"remove":         &k.roster.Remove,
+			"shuffle":        &k.roster.Shuffle,
+			"youngestFirst":  &k.roster.YoungestFirst,
+		}},
+		{"scoreKeeper", map[string]*key.Binding{
+			"nextFace":     &k.scoreKeeper.NextFace,
+			"previousFace": &k.scoreKeeper.PreviousFace,
+			"quit":         &k.scoreKeeper.Quit,
+			"submit":       &k.scoreKeeper.Submit,
+		}},
+		{"turns", map[string]*key.Binding{
+			"bottom":       &k.turns.Bottom,
+			"down":         &k.turns.Down,
+			"halfPageDown": &k.turns.HalfPageDown,
+			"halfPageUp":   &k.turns.HalfPageUp,
+			"top":          &k.turns.Top,
+			"up":           &k.turns.Up,
+		}},
+	}
+}
+
+/*
+apply replaces the keys of every binding named in overrides, relabelling them in the help to match.
+*/
+func (k *keyMaps) apply(overrides keyOverrides) error {
+	sections := k.sections()
+
+	for section, bindings := range overrides {
+		var named map[string]*key.Binding
+		for _, candidate := range sections {
+			if candidate.name == section {
+				named = candidate.bindings
+			}
+		}
+		if named == nil {
+			names := make([]string, len(sections))
+			for i, candidate := range sections {
+				names[i] = candidate.name
+			}
+			return fmt.Errorf("unknown section %q; expected one of %s", section, strings.Join(names, ", "))
+		}
+
+		for name, keys := range bindings {
+			binding, ok := named[name]
+			if !ok {
+				return fmt.Errorf("unknown binding %q in %s; expected one of %s", name, section, strings.Join(sortedNames(named), ", "))
+			}
+			if len(keys) == 0 {
+				return fmt.Errorf("%s.%s needs at least one key", section, name)
+			}
+
+			binding.SetKeys(keys...)
+			binding.SetHelp(keyLabel(keys[0]), binding.Help().Desc)
+		}
+	}
+
+	// the combined bindings follow whatever the turn and history keys are now
+	k.main.ScrollTurns = k.turns.combined(k.main.ScrollTurns.Help().Desc)
+	k.playback.ScrollTurns = k.turns.combined(k.playback.ScrollTurns.Help().Desc)
+	k.hotSeat.ScrollTurns = k.turns.combined(k.hotSeat.ScrollTurns.Help().Desc)
+	k.hotSeat.History = k.history.combined(k.hotSeat.History.Help().Desc)
+	k.main.History = k.history.combined(k.main.History.Help().Desc)
+	k.roster.History = k.history.combined(k.roster.History.Help().Desc)
+	k.scoreKeeper.History = k.history.combined(k.scoreKeeper.History.Help().Desc)
+
+	return nil
+}
+
+/*
+check makes sure that no key does two things in the same state.
+Shared keys, such as the turn keys, are checked against each state that uses them, and keys that type a character are
+refused when adding a player, as they belong to the name field.
+*/
+func (k *keyMaps) check() error {
+	var (
+		sections = k.sections()
+		shared   = make(map[string]map[string]*key.Binding, len(sharedSections))
+	)
+	for _, section := range sections {
+		if _, ok := sharedSections[section.name]; ok {
+			shared[section.name] = section.bindings
+		}
+	}
+
+	for _, section := range sections {
+		if _, ok := shared[section.name]; ok {
+			continue
+		}
+
+		named := make(map[string]*key.Binding, len(section.bindings))
+		for name, binding := range section.bindings {
+			named[name] = binding
+		}
+		for sharedName, bindings := range shared {
+			if !sharedSections[sharedName][section.name] {
+				continue
+			}
+			for name, binding := range bindings {
+				named[sharedName+"."+name] = binding
+			}
+		}
+
+		bound := make(map[string]string)
+		for _, name := range sortedNames(named) {
+			for _, keyName := range named[name].Keys() {
+				if section.name == "addPlayer" && utf8.RuneCountInString(keyName) == 1 {
+					return fmt.Errorf("%q can't be bound to %s.%s, as it's typed into the name", keyName, section.name, name)
+				}
+				if other, ok := bound[keyName]; ok {
+					return fmt.Errorf("%q is bound to both %s and %s in %s", keyName, other, name, section.name)
+				}
+				bound[keyName] = name
+			}
+		}
+	}
+
+	return nil
+}
+
+/*
+sortedNames returns the names of bindings in order, so that messages about them don't change from run to run.
+*/
+func sortedNames(named map[string]*key.Binding) []string {
+	names := make([]string, 0, len(named))
+	for name := range named {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+/*
+keyLabel returns how a key is shown in the help pane.
+*/
+func keyLabel(keyName string) string {
+	if strings.HasPrefix(keyName, "ctrl+") {
+		return "^" + strings.TrimPrefix(keyName, "ctrl+")
+	}
+
+	switch keyName {
+	case " ":
+		return "space"
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	case "shift+up":
+		return "⇧↑"
+	case "shift+down":
+		return "⇧↓"
+	case "pgdown":
+		return "pgdn"
+	default:
+		return keyName
+	}
+}
+
+/*
+parseKeys decodes a keys file: an optional "preset" naming the bindings to start from, and sections of overrides.
+Each override names a binding and lists its keys, for example {"preset": "vim", "main": {"quit": ["q", "ctrl+q"]}}.
+*/
+func parseKeys(data []byte) (string, keyOverrides, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return "", nil, err
+	}
+
+	var preset string
+	if raw, ok := fields["preset"]; ok {
+		if err := json.Unmarshal(raw, &preset); err != nil {
+			return "", nil, fmt.Errorf("preset: %s", err)
+		}
+		delete(fields, "preset")
+	}
+
+	overrides := make(keyOverrides, len(fields))
+	for section, raw := range fields {
+		var bindings map[string][]string
+		if err := json.Unmarshal(raw, &bindings); err != nil {
+			return "", nil, fmt.Errorf("%s: %s", section, err)
+		}
+		overrides[section] = bindings
+	}
+
+	return preset, overrides, nil
+}
+
+/*
+chooseKeys returns the key bindings to use: those of the preset called preset, changed by the keys file in the user's
+config directory if there is one.
+If preset is empty the keys file's own preset is used, or the defaults.
+*/
+func chooseKeys(preset string) (keyMaps, error) {
+	var (
+		keys      = defaultKeyMaps()
+		overrides keyOverrides
+	)
+
+	data, path, err := readConfig(keysFile)
+	if err != nil {
+		return keyMaps{}, err
+	}
+	if path != "" {
+		var filePreset string
+		if filePreset, overrides, err = parseKeys(data); err != nil {
+			return keyMaps{}, fmt.Errorf("invalid key bindings in %s: %s", path, err)
+		}
+		if preset == "" {
+			preset = filePreset
+		}
+	}
+
+	if preset != "" {
+		found := false
+		for _, candidate := range keyPresets {
+			if strings.EqualFold(preset, candidate.name) {
+				found = true
+				if err = keys.apply(candidate.overrides); err != nil {
+					return keyMaps{}, err
+				}
+			}
+		}
+		if !found {
+			return keyMaps{}, fmt.Errorf("unknown key preset %q; expected one of %s", preset, strings.Join(KeyPresetNames(), ", "))
+		}
+	}
+
+	if err = keys.apply(overrides); err != nil {
+		return keyMaps{}, fmt.Errorf("invalid key bindings in %s: %s", path, err)
+	}
+	if err = keys.check(); err != nil {
+		if path != "" {
+			return keyMaps{}, fmt.Errorf("conflicting key bindings in %s: %s", path, err)
+		}
+		return keyMaps{}, err
+	}
+
+	return keys, nil
+}
+
+/*
+keyPress returns the message that pressing the key called keyName sends, so that other input can act as that key.
+keyName is as in a binding's keys, such as "a", "enter", or "alt+shift+up".
+*/
+func keyPress(keyName string) tea.KeyMsg {
+	var press tea.KeyMsg
+	if strings.HasPrefix(keyName, "alt+") {
+		press.Alt = true
+		keyName = strings.TrimPrefix(keyName, "alt+")
+	}
+
+	// the named keys are all of the key types but runes
+	for keyType := tea.KeyF20; keyType <= tea.KeyBackspace; keyType++ {
+		if keyType != tea.KeyRunes && keyType.String() == keyName {
+			press.Type = keyType
+			return press
+		}
+	}
+
+	press.Type = tea.KeyRunes
+	press.Runes = []rune(keyName)
+	return press
+}
@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseKeys(t *testing.T) {
+	preset, overrides, err := parseKeys([]byte(`{"preset": "vim", "main": {"quit": ["x", "ctrl+q"]}, "turns": {"up": ["w"]}}`))
+	switch {
+	case err != nil:
+		t.Fatalf("failed to parse keys: %s", err)
+	case preset != "vim":
+		t.Fatalf("expected the vim preset but got %q", preset)
+	case !reflect.DeepEqual(overrides["main"]["quit"], []string{"x", "ctrl+q"}):
+		t.Fatalf("expected quit on x and ctrl+q but got %v", overrides["main"]["quit"])
+	case !reflect.DeepEqual(overrides["turns"]["up"], []string{"w"}):
+		t.Fatalf("expected scrolling up on w but got %v", overrides["turns"]["up"])
+	}
+
+	testCases := map[string]string{
+		"not an object":    `["main"]`,
+		"preset not named": `{"preset": 2}`,
+		"keys not listed":  `{"main": {"quit": "x"}}`,
+	}
+	for name, data := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := parseKeys([]byte(data)); err == nil {
+				t.Fatalf("shouldn't be able to parse %s", data)
+			}
+		})
+	}
+}
+
+func TestKeyMapsApply(t *testing.T) {
+	keys := defaultKeyMaps()
+	err := keys.apply(keyOverrides{
+		"main":    {"quit": {"x", "ctrl+q"}},
+		"history": {"undo": {"ctrl+z"}},
+		"turns":   {"up": {"w"}},
+	})
+	switch {
+	case err != nil:
+		t.Fatalf("failed to apply overrides: %s", err)
+	case !reflect.DeepEqual(keys.main.Quit.Keys(), []string{"x", "ctrl+q"}):
+		t.Fatalf("expected quit on x and ctrl+q but got %v", keys.main.Quit.Keys())
+	case keys.main.Quit.Help().Key != "x" || keys.history.Undo.Help().Key != "^z":
+		t.Fatalf("expected help labels x and ^z but got %s and %s", keys.main.Quit.Help().Key, keys.history.Undo.Help().Key)
+	}
+
+	// the combined bindings take up the new keys in every state that has them
+	for name, combined := range map[string][]string{
+		"main turns":           keys.main.ScrollTurns.Keys(),
+		"hot seat turns":       keys.hotSeat.ScrollTurns.Keys(),
+		"hot seat history":     keys.hotSeat.History.Keys(),
+		"roster history":       keys.roster.History.Keys(),
+		"score keeper history": keys.scoreKeeper.History.Keys(),
+	} {
+		expected := "w"
+		if strings.HasSuffix(name, "history") {
+			expected = "ctrl+z"
+		}
+		found := false
+		for _, keyName := range combined {
+			found = found || keyName == expected
+		}
+		if !found {
+			t.Fatalf("expected %s to include %s but got %v", name, expected, combined)
+		}
+	}
+
+	// the defaults themselves are left alone
+	if defaults := defaultKeyMaps(); !reflect.DeepEqual(defaults.main.Quit.Keys(), []string{"q", "ctrl+c"}) {
+		t.Fatalf("applying overrides changed the defaults; quit is now %v", defaults.main.Quit.Keys())
+	}
+}
+
+func TestKeyMapsApplyInvalid(t *testing.T) {
+	testCases := map[string]keyOverrides{
+		"unknown section": {"lobby": {"quit": {"x"}}},
+		"unknown binding": {"main": {"fly": {"f"}}},
+		"no keys":         {"main": {"quit": {}}},
+	}
+
+	for name, overrides := range testCases {
+		t.Run(name, func(t *testing.T) {
+			keys := defaultKeyMaps()
+			if err := keys.apply(overrides); err == nil {
+				t.Fatalf("shouldn't be able to apply %v", overrides)
+			}
+		})
+	}
+}
+
+func TestKeyMapsCheck(t *testing.T) {
+	for _, preset := range keyPresets {
+		t.Run(preset.name, func(t *testing.T) {
+			keys := defaultKeyMaps()
+			if err := keys.apply(preset.overrides); err != nil {
+				t.Fatalf("failed to apply the preset: %s", err)
+			}
+			if err := keys.check(); err != nil {
+				t.Fatalf("the preset's bindings conflict: %s", err)
+			}
+		})
+	}
+}
+
+func TestKeyMapsCheckConflicts(t *testing.T) {
+	testCases := map[string]keyOverrides{
+		"turns against main":     {"turns": {"down": {"a"}}},
+		"history against roster": {"history": {"undo": {"x"}}},
+		"history against turns":  {"history": {"redo": {"down"}}},
+		"same state":             {"main": {"quit": {"p"}}},
+		"typed into name":        {"addPlayer": {"submit": {"y"}}},
+		"next field on submit":   {"addPlayer": {"nextField": {"enter"}}},
+	}
+
+	for name, overrides := range testCases {
+		t.Run(name, func(t *testing.T) {
+			keys := defaultKeyMaps()
+			if err := keys.apply(overrides); err != nil {
+				t.Fatalf("failed to apply %v: %s", overrides, err)
+			}
+			if err := keys.check(); err == nil {
+				t.Fatalf("%v should conflict", overrides)
+			}
+		})
+	}
+}
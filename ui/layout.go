@@ -0,0 +1,322 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/bmoller/cherry-o/game"
+)
+
+/*
+arrangement is how the panes are placed relative to one another, chosen by the width of the terminal.
+*/
+type arrangement int
+
+const (
+	// stacked puts every pane in a single column, for narrow terminals.
+	stacked arrangement = iota
+	// underneath puts the players and help panes side by side below the main pane.
+	underneath
+	// sideBySide puts the players and help panes in a column to the right of the main pane, for wide terminals.
+	sideBySide
+)
+
+const (
+	// Size assumed until the terminal reports its own.
+	defaultWidth  int = 80
+	defaultHeight int = 66
+	// Narrowest terminal the stacked panes fit in, and the smallest main pane worth showing.
+	minWidth      int = 50
+	minMainHeight int = 12
+	// Narrowest terminals that fit the players and help panes beside each other and beside the main pane.
+	underneathWidth int = 78
+	sideBySideWidth int = 120
+	// Outer width of the players and help panes when they share a row, and of the column they form beside the main pane.
+	playersPaneWidth int = 32
+	helpPaneWidth    int = 42
+	// Outer height of the players and help panes, which don't grow with the terminal.
+	sidePaneHeight int = 12
+	// Widest the name input and lists used to add a player need to be.
+	fieldWidth int = playersPaneWidth - 2 - 2*2 - 3 // matches the players pane's content when they share a row, minus the selection prefix
+)
+
+/*
+layout holds the sizes of everything on screen, all worked out from the size of the terminal.
+Widths and heights of panes are of their content, inside any border and padding.
+*/
+type layout struct {
+	arrangement arrangement
+	// Whether the terminal is too small for any arrangement, in which case nothing but a notice is shown.
+	tooSmall bool
+	// Height the terminal needs to be for its arrangement when it's too small.
+	minHeight int
+	// Size of the terminal itself.
+	width  int
+	height int
+
+	mainHeight   int
+	mainWidth    int
+	helpWidth    int
+	playersWidth int
+}
+
+/*
+newLayout works out how to fill a terminal of width by height.
+*/
+func newLayout(width int, height int) layout {
+	l := layout{
+		width:  width,
+		height: height,
+	}
+	// the main pane has a margin of 2 either side and 1 above and below, plus its border
+	mainFrameWidth, mainFrameHeight := 2*2+2, 2*1+2
+	// side panes have a border plus padding of 2 either side
+	sideFrameWidth := 2 + 2*2
+
+	switch {
+	case width >= sideBySideWidth:
+		l.arrangement = sideBySide
+		l.mainWidth = width - mainFrameWidth - helpPaneWidth - 2*2 // the side column has margins too
+		l.mainHeight = height - mainFrameHeight - 1
+		l.playersWidth = helpPaneWidth - sideFrameWidth
+		l.helpWidth = helpPaneWidth - sideFrameWidth
+	case width >= underneathWidth:
+		l.arrangement = underneath
+		l.mainWidth = width - mainFrameWidth
+		l.mainHeight = height - mainFrameHeight - sidePaneHeight - 1
+		l.playersWidth = playersPaneWidth - sideFrameWidth
+		l.helpWidth = helpPaneWidth - sideFrameWidth
+	default:
+		l.arrangement = stacked
+		l.mainWidth = width - mainFrameWidth
+		l.mainHeight = height - mainFrameHeight - 2*sidePaneHeight - 1
+		l.playersWidth = width - 2*2 - sideFrameWidth
+		l.helpWidth = width - 2*2 - sideFrameWidth
+	}
+	if width < minWidth || l.mainHeight < minMainHeight {
+		// keep the components at a workable size until the terminal grows again
+		small := newLayout(defaultWidth, defaultHeight)
+		small.tooSmall = true
+		small.width, small.height = width, height
+		small.minHeight = height - l.mainHeight + minMainHeight
+		return small
+	}
+
+	return l
+}
+
+/*
+fieldWidth returns the width of the inputs used to add a player, which shrink to fit a narrow main pane.
+*/
+func (l layout) fieldWidth() int {
+	// the add player box has a border and padding of 2 either side, and lists prefix their items
+	available := l.mainWidth - 2 - 2*2 - 3
+	if available < fieldWidth {
+		return available
+	}
+	return fieldWidth
+}
+
+/*
+listHeight returns the height of a list with items rows, shrunk if needed so that it fits in the main pane along with
+frame further rows.
+*/
+func (l layout) listHeight(items int, frame int) int {
+	if available := l.mainHeight - frame; available < items {
+		return available
+	}
+	return items
+}
+
+/*
+fitLists sets the heights of m's lists to show all of their items, or as many as fit in the main pane.
+*/
+func fitLists(m model) model {
+	// the add player box has a border, padding, a title, and the name and age inputs with a line below them
+	m.colorList.SetHeight(m.layout.listHeight(len(game.Colors())+2, 2+2+2+3)) // room for every color plus the title
+	// the score keeper's box has a border, padding, and a title with a line below it, and the last turn above it
+	m.faceList.SetHeight(m.layout.listHeight(len(m.faceList.Items()), 2+2+3+2))
+
+	return m
+}
+
+/*
+resize lays m out for a terminal of width by height, sizing the components that don't take their size from a pane.
+*/
+func resize(m model, width int, height int) model {
+	m.layout = newLayout(width, height)
+
+	fields := m.layout.fieldWidth()
+	m.nameInput.Width = fields
+	m.ageInput.Width = fields
+	m.colorList.SetWidth(fields)
+	m.faceList.SetWidth(fields)
+	m = fitLists(m)
+	m.bindHelp.Width = m.layout.helpWidth
+	m.turnView.Width = m.layout.mainWidth
+	m.turnView.Height = m.layout.mainHeight
+
+	// the turn list is wrapped to the width of the pane, so it has to be drawn again
+	if len(m.playback.turns) > 0 {
+		if m.state == hotSeatState {
+			m = refreshMatch(m)
+		} else {
+			m = refreshPlayback(m)
+		}
+	}
+
+	return m
+}
+
+/*
+region is a rectangle of the screen, measured in cells from the top left corner.
+*/
+type region struct {
+	x      int
+	y      int
+	width  int
+	height int
+}
+
+/*
+contains reports whether the cell at x and y is within r.
+*/
+func (r region) contains(x int, y int) bool {
+	return x >= r.x && x < r.x+r.width && y >= r.y && y < r.y+r.height
+}
+
+/*
+mainRegion returns where the content of the main pane is drawn by assemble.
+*/
+func (l layout) mainRegion() region {
+	// inside the main pane's margin and border
+	return region{x: 2 + 1, y: 1 + 1, width: l.mainWidth, height: l.mainHeight}
+}
+
+/*
+playersRegion returns where the content of the players pane is drawn by assemble.
+*/
+func (l layout) playersRegion() region {
+	var (
+		content = l.sidePaneContent(l.playersWidth)
+		// below the main pane and its margin
+		below = l.mainHeight + 2 + 2*1
+	)
+
+	switch l.arrangement {
+	case sideBySide:
+		// in the column beside the main pane, below a margin of 1
+		content.x += l.mainWidth + 2 + 2*2 + 2
+		content.y += 1
+	default:
+		content.x += 2
+		content.y += below
+	}
+
+	return content
+}
+
+/*
+helpRegion returns where the content of the help pane is drawn by assemble.
+*/
+func (l layout) helpRegion() region {
+	var (
+		content = l.sidePaneContent(l.helpWidth)
+		below   = l.mainHeight + 2 + 2*1
+	)
+
+	switch l.arrangement {
+	case sideBySide:
+		// below the players pane in the column beside the main pane
+		content.x += l.mainWidth + 2 + 2*2 + 2
+		content.y += 1 + sidePaneHeight
+	case underneath:
+		// beside the players pane, its padding and border, and its margins
+		content.x += l.playersWidth + 2*2 + 2 + 2*2
+		content.y += below
+	default:
+		content.x += 2
+		content.y += below + sidePaneHeight
+	}
+
+	return content
+}
+
+/*
+sidePaneContent returns the content area of a side pane width wide, relative to the pane's top left corner.
+*/
+func (l layout) sidePaneContent(width int) region {
+	return region{
+		// side panes have a border, padding of 2 either side, and padding of 1 above and below
+		x:      1 + 2,
+		y:      1 + 1,
+		width:  width,
+		height: sidePaneHeight - 2 - 2*1,
+	}
+}
+
+/*
+assemble puts the content pieces together according to l's arrangement.
+The playersContent, helpContent, and mainContent are each placed in their own pane, bordered in theme's colors.
+appStates simply need to determine what they want placed in each pane and pass the content to this function.
+*/
+func (l layout) assemble(theme Theme, playersContent string, helpContent string, mainContent string) string {
+	var (
+		main    = mainPane.Copy().Width(l.mainWidth).Height(l.mainHeight).BorderForeground(theme.Border.color())
+		players = playersPane.Copy().Width(l.playersWidth + playersPane.GetHorizontalPadding()).BorderForeground(theme.Border.color())
+		help    = helpPane.Copy().Width(l.helpWidth + helpPane.GetHorizontalPadding()).BorderForeground(theme.HelpBorder.color())
+	)
+
+	switch l.arrangement {
+	case sideBySide:
+		return lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			main.Render(mainContent),
+			lipgloss.JoinVertical(
+				lipgloss.Left,
+				players.Copy().MarginTop(1).Render(playersContent),
+				help.Copy().Margin(0, 2).Render(helpContent),
+			),
+		)
+	case underneath:
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			main.Render(mainContent),
+			lipgloss.JoinHorizontal(
+				lipgloss.Top,
+				players.Render(playersContent),
+				help.Render(helpContent),
+			),
+		)
+	default:
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			main.Render(mainContent),
+			players.Render(playersContent),
+			help.Copy().Margin(0, 2).Render(helpContent),
+		)
+	}
+}
+
+var styleTooSmall = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder(), true).
+	Padding(0, 1)
+
+/*
+viewTooSmall asks the user for a bigger terminal, in place of everything else.
+*/
+func viewTooSmall(l layout, theme Theme) string {
+	message := fmt.Sprintf("🍒 This window is too small for Cherry-O! 🍒\n\nPlease make it at least %d wide and %d tall; it's %d by %d now.",
+		minWidth, l.minHeight, l.width, l.height)
+
+	// wrap the message to fit, leaving room for the border
+	wrap := l.width - 2
+	if wrap > minWidth-2 {
+		wrap = minWidth - 2
+	}
+
+	return lipgloss.Place(l.width, l.height,
+		lipgloss.Center, lipgloss.Center,
+		styleTooSmall.Copy().BorderForeground(theme.Border.color()).Width(wrap).Align(lipgloss.Center).Render(message))
+}
@@ -3,21 +3,27 @@ package ui
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
-	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/bmoller/cherry-o/game"
+	"github.com/bmoller/cherry-o/game/replay"
 )
 
 type mainKeyMap struct {
-	AddPlayer    key.Binding
-	Play         key.Binding
-	Quit         key.Binding
-	RemovePlayer key.Binding
-	ScrollDown   key.Binding
-	ScrollUp     key.Binding
+	AddPlayer         key.Binding
+	History           key.Binding
+	KeepScore         key.Binding
+	Play              key.Binding
+	Quit              key.Binding
+	Roster            key.Binding
+	ScrollPlayersDown key.Binding
+	ScrollPlayersUp   key.Binding
+	ScrollTurns       key.Binding
+	Simulate          key.Binding
+	ToggleBoard       key.Binding
 }
 
 func (k mainKeyMap) ShortHelp() []key.Binding {
@@ -26,8 +32,8 @@ func (k mainKeyMap) ShortHelp() []key.Binding {
 
 func (k mainKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.AddPlayer, k.ScrollUp, k.ScrollDown},
-		{k.Play, k.RemovePlayer, k.Quit},
+		{k.AddPlayer, k.Roster, k.Play, k.Simulate, k.KeepScore},
+		{k.Quit, k.ToggleBoard, k.ScrollTurns, k.ScrollPlayersUp, k.ScrollPlayersDown, k.History},
 	}
 }
 
@@ -36,6 +42,11 @@ var mainKeyBinds = mainKeyMap{
 		key.WithKeys("a"),
 		key.WithHelp("a", "Add player"),
 	),
+	History: historyKeyBinds.combined("Undo/redo"),
+	KeepScore: key.NewBinding(
+		key.WithKeys("k"),
+		key.WithHelp("k", "Keep score"),
+	),
 	Play: key.NewBinding(
 		key.WithKeys("p"),
 		key.WithHelp("p", "Play game"),
@@ -44,65 +55,167 @@ var mainKeyBinds = mainKeyMap{
 		key.WithKeys("q", "ctrl+c"),
 		key.WithHelp("q", "Quit game"),
 	),
-	RemovePlayer: key.NewBinding(
+	Roster: key.NewBinding(
 		key.WithKeys("r"),
-		key.WithHelp("r", "Remove player"),
+		key.WithHelp("r", "Edit roster"),
+	),
+	ScrollPlayersDown: key.NewBinding(
+		key.WithKeys("shift+down"),
+		key.WithHelp("⇧↓", "Players down"),
+	),
+	ScrollPlayersUp: key.NewBinding(
+		key.WithKeys("shift+up"),
+		key.WithHelp("⇧↑", "Players up"),
+	),
+	ScrollTurns: turnKeyBinds.combined("Scroll turns"),
+	Simulate: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "Simulate game"),
 	),
-	ScrollDown: key.NewBinding(
+	ToggleBoard: key.NewBinding(
+		key.WithKeys("b"),
+		key.WithHelp("b", "Board view"),
+	),
+}
+
+/*
+turnKeyMap holds the keys that scroll the turn list, shared by every state that shows it.
+*/
+type turnKeyMap struct {
+	Bottom       key.Binding
+	Down         key.Binding
+	HalfPageDown key.Binding
+	HalfPageUp   key.Binding
+	Top          key.Binding
+	Up           key.Binding
+}
+
+var turnKeyBinds = turnKeyMap{
+	Bottom: key.NewBinding(
+		key.WithKeys("end"),
+		key.WithHelp("end", "Last turn"),
+	),
+	Down: key.NewBinding(
 		key.WithKeys("down"),
 		key.WithHelp("↓", "Scroll down"),
 	),
-	ScrollUp: key.NewBinding(
+	HalfPageDown: key.NewBinding(
+		key.WithKeys("pgdown"),
+		key.WithHelp("pgdn", "Half page down"),
+	),
+	HalfPageUp: key.NewBinding(
+		key.WithKeys("pgup"),
+		key.WithHelp("pgup", "Half page up"),
+	),
+	Top: key.NewBinding(
+		key.WithKeys("home"),
+		key.WithHelp("home", "First turn"),
+	),
+	Up: key.NewBinding(
 		key.WithKeys("up"),
 		key.WithHelp("↑", "Scroll up"),
 	),
 }
 
+/*
+combined returns a single binding for every key in k, so that scrolling takes one row of the help pane.
+It's labelled with the keys that scroll a line at a time.
+*/
+func (k turnKeyMap) combined(desc string) key.Binding {
+	var keys []string
+	for _, binding := range []key.Binding{k.Up, k.Down, k.HalfPageUp, k.HalfPageDown, k.Top, k.Bottom} {
+		keys = append(keys, binding.Keys()...)
+	}
+
+	return key.NewBinding(
+		key.WithKeys(keys...),
+		key.WithHelp(k.Up.Help().Key+"/"+k.Down.Help().Key, desc),
+	)
+}
+
+/*
+scrollTurns moves m's turn list as msg asks, by a line, half a page, or to either end.
+*/
+func scrollTurns(m model, msg tea.KeyMsg) model {
+	switch {
+	case key.Matches(msg, m.keys.turns.Up):
+		m.turnView.LineUp(1)
+	case key.Matches(msg, m.keys.turns.Down):
+		m.turnView.LineDown(1)
+	case key.Matches(msg, m.keys.turns.HalfPageUp):
+		m.turnView.HalfViewUp()
+	case key.Matches(msg, m.keys.turns.HalfPageDown):
+		m.turnView.HalfViewDown()
+	case key.Matches(msg, m.keys.turns.Top):
+		m.turnView.GotoTop()
+	case key.Matches(msg, m.keys.turns.Bottom):
+		m.turnView.GotoBottom()
+	}
+
+	return m
+}
+
 func updateMainState(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch {
-		case key.Matches(msg, mainKeyBinds.AddPlayer):
-			if m.game.PlayerCount() == game.MaxPlayers {
-				m.err = fmt.Errorf("only %d players can play at a time", game.MaxPlayers)
+		case key.Matches(msg, m.keys.main.AddPlayer):
+			switch maxPlayers := m.game.Rules().MaxPlayers; {
+			case m.game.PlayerCount() >= maxPlayers:
+				m.err = fmt.Errorf("only %d players can play at a time", maxPlayers)
 				m.state = errorState
-			} else {
-				var (
-					cmds      [2]tea.Cmd
-					colorList []list.Item
-				)
-				for color, available := range m.game.AvailableColors() {
-					if available {
-						colorList = append(colorList, color)
-					}
-				}
-				cmds[0] = m.nameInput.Focus()
-				cmds[1] = m.colorList.SetItems(colorList)
-				m.colorList.ResetSelected()
-				cmd = tea.Batch(cmds[:]...)
+			case !colorsFree(m.game):
+				m.err = errors.New("every color is taken; remove a player to free one up")
+				m.state = errorState
+			default:
+				m, cmd = startPlayerForm(m, game.Player{})
 				m.state = addPlayerState
 			}
-		case key.Matches(msg, mainKeyBinds.Play):
-			if turns, winner, err := m.game.Play(); err != nil {
+		case key.Matches(msg, m.keys.main.Play):
+			if started, err := startHotSeat(m); err != nil {
 				m.err = err
 				m.state = errorState
 			} else {
-				m.turnView.SetContent(renderTurns(turns))
-				m.winner = winner
+				m = started
 			}
-		case key.Matches(msg, mainKeyBinds.Quit):
+		case key.Matches(msg, m.keys.main.KeepScore):
+			var err error
+			if m, cmd, err = startScoreKeeper(m); err != nil {
+				m.err = err
+				m.state = errorState
+			}
+		case key.Matches(msg, m.keys.main.Simulate):
+			if recording, err := recordGame(m.game); err != nil {
+				m.err = err
+				m.state = errorState
+			} else {
+				m, cmd = startPlayback(m, recording)
+				if m.recordPath != "" {
+					cmd = tea.Batch(cmd, saveReplay(recording, m.recordPath))
+				}
+			}
+		case key.Matches(msg, m.keys.main.Quit):
 			cmd = tea.Quit
-		case key.Matches(msg, mainKeyBinds.RemovePlayer):
+		case key.Matches(msg, m.keys.main.Roster):
 			if m.game.PlayerCount() == 0 {
-				m.err = errors.New("no players to remove")
+				m.err = errors.New("add a player before editing the roster")
 				m.state = errorState
 			} else {
-				m.state = removePlayerState
+				m = moveSelection(m, 0)
+				m.state = rosterState
 			}
-		case key.Matches(msg, mainKeyBinds.ScrollDown, mainKeyBinds.ScrollUp):
-			m.turnView, cmd = m.turnView.Update(msg)
+		case key.Matches(msg, m.keys.main.ToggleBoard):
+			m.showBoard = !m.showBoard
+		case key.Matches(msg, m.keys.main.History):
+			m = stepHistory(m, msg)
+		case key.Matches(msg, m.keys.main.ScrollTurns):
+			m = scrollTurns(m, msg)
+		case key.Matches(msg, m.keys.main.ScrollPlayersDown):
+			m = scrollPlayers(m, 1)
+		case key.Matches(msg, m.keys.main.ScrollPlayersUp):
+			m = scrollPlayers(m, -1)
 		}
 	default:
 		m.turnView, cmd = m.turnView.Update(msg)
@@ -111,50 +224,86 @@ func updateMainState(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-func renderTurns(turns []game.Turn) string {
-	// spinnerValues = [7]int{1, 2, 3, 4, -2, -2, -10}
-	var (
-		format string
-		// output   strings.Builder
-		output   string
-		renderer func(string) string
-	)
+/*
+recordGame plays a copy of g with a freshly seeded spinner so that the game can be replayed later.
+*/
+func recordGame(g game.Game) (replay.Replay, error) {
+	seed, err := game.NewSeed()
+	if err != nil {
+		return replay.Replay{}, err
+	}
 
-	for _, turn := range turns {
-		switch turn.Spin {
-		case -10:
-			format = "Oh no! %s lost 10 cherries!\n"
-		case -2:
-			format = "Uh-oh, %s lost 2 cherries.\n"
-		case 1:
-			format = "%s got another cherry.\n"
-		case 2:
-			format = "Hey, %s got 2 more cherries!\n"
-		case 3:
-			format = "Yay, %s got 3 more cherries!\n"
-		case 4:
-			format = "Hooray, %s got 4 more cherries!\n"
-		}
+	return replay.Record(g, seed)
+}
 
-		switch turn.Player.Color() {
-		case game.Blue:
-			renderer = styleBlue.Render
-		case game.Green:
-			renderer = styleGreen.Render
-		case game.Red:
-			renderer = styleRed.Render
-		case game.Yellow:
-			renderer = styleYellow.Render
+/*
+saveReplay returns a command that writes recording to path, reporting any failure as an errMsg.
+*/
+func saveReplay(recording replay.Replay, path string) tea.Cmd {
+	return func() tea.Msg {
+		if err := recording.Save(path); err != nil {
+			return errMsg{fmt.Errorf("failed to save replay: %s", err)}
 		}
+		return nil
+	}
+}
+
+/*
+narrateTurn describes turn in a friendly sentence, using the face's identity rather than its effect where it matters.
+In m's accessible mode the player's color is named too.
+*/
+func narrateTurn(m model, turn game.Turn) string {
+	name := spokenName(m, turn.Player)
+
+	switch face := turn.Face; {
+	case face.ID == game.BucketID:
+		return fmt.Sprintf("Oh no! %s spilled the whole bucket!", name)
+	case face.ID == game.BirdID:
+		return fmt.Sprintf("Uh-oh, a bird flew off with %d of %s's cherries.", -face.Effect, name)
+	case face.ID == game.DogID:
+		return fmt.Sprintf("Uh-oh, the dog knocked over %s's bucket and %d cherries rolled away.", name, -face.Effect)
+	case face.Spill:
+		return fmt.Sprintf("Oh no! %s spun %s and lost every cherry!", name, face.Name)
+	case face.Effect < 0:
+		return fmt.Sprintf("Uh-oh, %s spun %s and lost %d cherries.", name, face.Name, -face.Effect)
+	case face.Effect == 1:
+		return fmt.Sprintf("%s got another cherry.", name)
+	case face.Effect == 2:
+		return fmt.Sprintf("Hey, %s got 2 more cherries!", name)
+	case face.Effect == 3:
+		return fmt.Sprintf("Yay, %s got 3 more cherries!", name)
+	default:
+		return fmt.Sprintf("Hooray, %s got %d more cherries!", name, face.Effect)
+	}
+}
+
+/*
+renderTurns narrates each of turns on its own line in m's theme, wrapping lines longer than width.
+*/
+func renderTurns(m model, turns []game.Turn, width int) string {
+	var output strings.Builder
+
+	for _, turn := range turns {
+		style := m.theme.playerStyle(turn.Player.Color()).Width(width)
+		output.WriteString(style.Render(turn.Face.Symbol+" "+narrateTurn(m, turn)) + "\n\n")
+	}
+
+	return output.String()
+}
 
-		//output.WriteString(renderer(fmt.Sprintf(format, turn.Player.Name)))
-		output += renderer(fmt.Sprintf(format, turn.Player.Name)) + "\n"
+/*
+latestTurnCaption narrates the most recent turn shown, if there is one.
+*/
+func latestTurnCaption(m model) string {
+	turns := m.playback.turns[:m.playback.shown]
+	if len(turns) == 0 {
+		return ""
 	}
 
-	//return output.String()
-	return output
+	turn := turns[len(turns)-1]
+	return m.theme.playerStyle(turn.Player.Color()).Render(turn.Face.Symbol + " " + narrateTurn(m, turn))
 }
 
 func viewMainState(m model) string {
-	return assembleView(renderPlayers(m, -1), renderHelpContent(m, mainKeyBinds), m.turnView.View())
+	return m.layout.assemble(m.theme, renderPlayers(m, 0), renderHelpContent(m, m.keys.main), renderMainContent(m, latestTurnCaption(m)))
 }
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+/*
+updateMouse handles clicks and the scroll wheel wherever they land, passing anything else on to the current state.
+Clicking an entry in the help pane acts as pressing its key, so every state gets those clicks for free.
+*/
+func updateMouse(msg tea.MouseMsg, m model) (tea.Model, tea.Cmd) {
+	var (
+		helpArea    = m.layout.helpRegion()
+		mainArea    = m.layout.mainRegion()
+		playersArea = m.layout.playersRegion()
+	)
+
+	switch msg.Type {
+	case tea.MouseLeft:
+		switch {
+		case helpArea.contains(msg.X, msg.Y):
+			if binding, ok := helpBindingAt(m, m.state.keyMap(m), msg.X-helpArea.x, msg.Y-helpArea.y); ok && len(binding.Keys()) > 0 {
+				return m.state.update(keyPress(binding.Keys()[0]), m)
+			}
+		case playersArea.contains(msg.X, msg.Y):
+			return clickPlayer(m, msg.Y-playersArea.y)
+		case mainArea.contains(msg.X, msg.Y) && (m.state == addPlayerState || m.state == editPlayerState):
+			if index, ok := colorAt(m, msg.X-mainArea.x, msg.Y-mainArea.y); ok {
+				m.colorList.Select(index)
+			}
+		}
+		return m, nil
+	case tea.MouseWheelUp, tea.MouseWheelDown:
+		// the wheel scrolls whichever of the players pane and the turn list it's over
+		if playersArea.contains(msg.X, msg.Y) && m.state == mainState {
+			offset := 1
+			if msg.Type == tea.MouseWheelUp {
+				offset = -1
+			}
+			return scrollPlayers(m, offset), nil
+		}
+	}
+
+	return m.state.update(msg, m)
+}
+
+/*
+clickPlayer selects the player listed at row of the players pane's content.
+From the main state this opens the roster with the clicked player already selected.
+*/
+func clickPlayer(m model, row int) (tea.Model, tea.Cmd) {
+	switch m.state {
+	case mainState:
+		if player, ok := playerAt(m, 0, row); ok {
+			m.selectedPlayer = player.ID()
+			m.state = rosterState
+			m = moveSelection(m, 0)
+		}
+	case rosterState:
+		if player, ok := playerAt(m, m.selectedPlayer, row); ok {
+			m.selectedPlayer = player.ID()
+			m = moveSelection(m, 0)
+		}
+	}
+
+	return m, nil
+}
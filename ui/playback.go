@@ -0,0 +1,293 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/bmoller/cherry-o/game"
+	"github.com/bmoller/cherry-o/game/replay"
+)
+
+const (
+	// How long each turn takes to appear at normal speed, including its spin.
+	baseTurnDuration = time.Second
+	// Number of spinner frames shown before each turn is revealed.
+	spinFrames int = 6
+	// Index into playbackSpeeds used when playback starts.
+	defaultSpeed int = 1
+)
+
+// Multipliers the user can pick between for playback speed.
+var playbackSpeeds = []float64{0.5, 1, 2, 4, 8}
+
+/*
+playback tracks the turns of a recorded game as they are revealed one at a time.
+A game that has been fully revealed is kept around so that the final standings stay on screen.
+*/
+type playback struct {
+	// Spinner frame of the turn being revealed next.
+	frame int
+	// Identifies the current run of ticks; ticks from an earlier run are ignored.
+	generation int
+	paused     bool
+	// Number of turns revealed so far.
+	shown int
+	// Index into playbackSpeeds.
+	speed  int
+	turns  []game.Turn
+	winner game.Player
+}
+
+/*
+playbackTickMsg advances the spinner animation of the playback started with the same generation.
+*/
+type playbackTickMsg struct {
+	generation int
+}
+
+/*
+done reports whether every turn of p has been revealed.
+*/
+func (p playback) done() bool {
+	return p.shown >= len(p.turns)
+}
+
+/*
+standings returns how many cherries each player holds after the turns revealed so far.
+Players who haven't taken a turn yet are left out.
+*/
+func (p playback) standings() map[game.PlayerID]int {
+	cherries := make(map[game.PlayerID]int)
+	for _, turn := range p.turns[:p.shown] {
+		cherries[turn.Player.ID()] = turn.Player.Cherries()
+	}
+
+	return cherries
+}
+
+type playbackKeyMap struct {
+	Faster      key.Binding
+	Pause       key.Binding
+	ScrollTurns key.Binding
+	Skip        key.Binding
+	Slower      key.Binding
+	Step        key.Binding
+	ToggleBoard key.Binding
+}
+
+func (k playbackKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Pause, k.Skip}
+}
+
+func (k playbackKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Pause, k.Step, k.Faster, k.Slower},
+		{k.Skip, k.ScrollTurns, k.ToggleBoard},
+	}
+}
+
+var playbackKeyBinds = playbackKeyMap{
+	Faster: key.NewBinding(
+		key.WithKeys("+", "="),
+		key.WithHelp("+", "Faster"),
+	),
+	Pause: key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "Pause"),
+	),
+	ScrollTurns: turnKeyBinds.combined("Scroll turns"),
+	Skip: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "Skip to end"),
+	),
+	Slower: key.NewBinding(
+		key.WithKeys("-"),
+		key.WithHelp("-", "Slower"),
+	),
+	Step: key.NewBinding(
+		key.WithKeys("n", "right"),
+		key.WithHelp("n/→", "Next turn"),
+	),
+	ToggleBoard: key.NewBinding(
+		key.WithKeys("b"),
+		key.WithHelp("b", "Board view"),
+	),
+}
+
+/*
+startPlayback begins revealing the turns of recording in m, returning the command that drives the animation.
+*/
+func startPlayback(m model, recording replay.Replay) (model, tea.Cmd) {
+	winner, _ := recording.Winner()
+	m = forgetScoring(m)
+	m.playback = playback{
+		generation: m.playback.generation + 1,
+		speed:      defaultSpeed,
+		turns:      recording.Turns,
+		winner:     winner,
+	}
+	m.winner = game.Player{}
+	m.state = playbackState
+	m = refreshPlayback(m)
+	m.turnView.GotoTop()
+
+	return m, playbackTick(m.playback)
+}
+
+/*
+clearPlayback forgets the game being shown in m, such as when the roster changes and its standings no longer apply.
+*/
+func clearPlayback(m model) model {
+	m.playback = playback{generation: m.playback.generation}
+	return m
+}
+
+/*
+playbackTick returns a command that delivers the next animation frame of p at its current speed.
+*/
+func playbackTick(p playback) tea.Cmd {
+	interval := time.Duration(float64(baseTurnDuration) / float64(spinFrames) / playbackSpeeds[p.speed])
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return playbackTickMsg{p.generation}
+	})
+}
+
+/*
+revealTurn shows the next turn of m's playback, finishing playback if it was the last.
+*/
+func revealTurn(m model) model {
+	m.playback.frame = 0
+	m.playback.shown++
+	if m.playback.done() {
+		return finishPlayback(m)
+	}
+
+	return refreshPlayback(m)
+}
+
+/*
+finishPlayback reveals every remaining turn and returns m to the mainState.
+*/
+func finishPlayback(m model) model {
+	m.playback.shown = len(m.playback.turns)
+	m.playback.generation++
+	m.winner = m.playback.winner
+	m.state = mainState
+	m = refreshPlayback(m)
+	m.turnView.GotoBottom()
+
+	return m
+}
+
+/*
+playbackCaption describes the spin in progress in m's playback, or returns an empty string once every turn is shown.
+*/
+func playbackCaption(m model) string {
+	if m.playback.done() {
+		return ""
+	}
+
+	var (
+		faces   = m.game.Rules().Faces
+		next    = m.playback.turns[m.playback.shown]
+		speed   = playbackSpeeds[m.playback.speed]
+		caption = fmt.Sprintf("%s %s is spinning… (%gx)", faces[m.playback.frame%len(faces)].Symbol, spokenName(m, next.Player), speed)
+	)
+	if m.playback.paused {
+		caption = fmt.Sprintf("⏸ Paused before %s's turn (%gx)", spokenName(m, next.Player), speed)
+	}
+
+	return m.theme.playerStyle(next.Player.Color()).Render(caption)
+}
+
+/*
+refreshPlayback renders the revealed turns into m's turn list, followed by the spinner while turns remain.
+The list follows new turns unless the user has scrolled away from the bottom.
+*/
+func refreshPlayback(m model) model {
+	var (
+		follow  = m.turnView.AtBottom()
+		width   = m.turnView.Width - m.turnView.Style.GetHorizontalFrameSize()
+		content = renderTurns(m, m.playback.turns[:m.playback.shown], width)
+	)
+
+	if caption := playbackCaption(m); caption != "" {
+		content += lipgloss.NewStyle().Width(width).Render(caption)
+	}
+
+	m.turnView.SetContent(content)
+	if follow {
+		m.turnView.GotoBottom()
+	}
+
+	return m
+}
+
+func updatePlaybackState(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case playbackTickMsg:
+		if msg.generation != m.playback.generation || m.playback.paused {
+			break
+		}
+		if m.playback.frame++; m.playback.frame >= spinFrames {
+			m = revealTurn(m)
+		} else {
+			m = refreshPlayback(m)
+		}
+		if m.state == playbackState {
+			cmd = playbackTick(m.playback)
+		}
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.playback.Pause):
+			m.playback.paused = !m.playback.paused
+			m.playback.generation++
+			if !m.playback.paused {
+				cmd = playbackTick(m.playback)
+			}
+			m = refreshPlayback(m)
+		case key.Matches(msg, m.keys.playback.Step):
+			m = revealTurn(m)
+			if m.state == playbackState && !m.playback.paused {
+				// start the next spin from the beginning rather than partway through
+				m.playback.generation++
+				cmd = playbackTick(m.playback)
+			}
+		case key.Matches(msg, m.keys.playback.Faster):
+			if m.playback.speed < len(playbackSpeeds)-1 {
+				m.playback.speed++
+			}
+			m = refreshPlayback(m)
+		case key.Matches(msg, m.keys.playback.Slower):
+			if m.playback.speed > 0 {
+				m.playback.speed--
+			}
+			m = refreshPlayback(m)
+		case key.Matches(msg, m.keys.playback.ToggleBoard):
+			m.showBoard = !m.showBoard
+		case key.Matches(msg, m.keys.playback.Skip):
+			m = finishPlayback(m)
+		case key.Matches(msg, m.keys.playback.ScrollTurns):
+			m = scrollTurns(m, msg)
+		}
+	default:
+		m.turnView, cmd = m.turnView.Update(msg)
+	}
+
+	return m, cmd
+}
+
+func viewPlaybackState(m model) string {
+	var current game.PlayerID
+	if !m.playback.done() {
+		current = m.playback.turns[m.playback.shown].Player.ID()
+	}
+
+	return m.layout.assemble(m.theme, renderPlayers(m, current), renderHelpContent(m, m.keys.playback), renderMainContent(m, playbackCaption(m)))
+}
@@ -0,0 +1,187 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/bmoller/cherry-o/game"
+)
+
+type rosterKeyMap struct {
+	Done           key.Binding
+	Edit           key.Binding
+	History        key.Binding
+	MoveDown       key.Binding
+	MoveUp         key.Binding
+	NextPlayer     key.Binding
+	PreviousPlayer key.Binding
+	Remove         key.Binding
+	Shuffle        key.Binding
+	YoungestFirst  key.Binding
+}
+
+func (k rosterKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Edit, k.Remove, k.Done}
+}
+
+func (k rosterKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.PreviousPlayer, k.NextPlayer, k.MoveUp, k.MoveDown},
+		{k.Edit, k.Remove, k.Shuffle, k.YoungestFirst, k.History, k.Done},
+	}
+}
+
+var rosterKeyBinds = rosterKeyMap{
+	Done: key.NewBinding(
+		key.WithHelp("esc", "Done"),
+		key.WithKeys("esc"),
+	),
+	Edit: key.NewBinding(
+		key.WithHelp("e", "Edit"),
+		key.WithKeys("e", "enter"),
+	),
+	History: historyKeyBinds.combined("Undo/redo"),
+	MoveDown: key.NewBinding(
+		key.WithHelp("⇧↓", "Move down"),
+		key.WithKeys("shift+down"),
+	),
+	MoveUp: key.NewBinding(
+		key.WithHelp("⇧↑", "Move up"),
+		key.WithKeys("shift+up"),
+	),
+	NextPlayer: key.NewBinding(
+		key.WithHelp("↓", "Next"),
+		key.WithKeys("down"),
+	),
+	PreviousPlayer: key.NewBinding(
+		key.WithHelp("↑", "Previous"),
+		key.WithKeys("up"),
+	),
+	Remove: key.NewBinding(
+		key.WithHelp("x", "Remove"),
+		key.WithKeys("x", "delete"),
+	),
+	Shuffle: key.NewBinding(
+		key.WithHelp("s", "Shuffle seats"),
+		key.WithKeys("s"),
+	),
+	YoungestFirst: key.NewBinding(
+		key.WithHelp("y", "Youngest first"),
+		key.WithKeys("y"),
+	),
+}
+
+/*
+moveSelection moves m's selected player by offset seats, stopping at either end of the roster.
+If nobody is selected yet the first player is selected.
+*/
+func moveSelection(m model, offset int) model {
+	players := m.game.Players()
+	if len(players) == 0 {
+		m.selectedPlayer = 0
+		return m
+	}
+
+	index := 0
+	for i, player := range players {
+		if player.ID() == m.selectedPlayer {
+			index = i + offset
+		}
+	}
+	if index < 0 {
+		index = 0
+	} else if index >= len(players) {
+		index = len(players) - 1
+	}
+	m.selectedPlayer = players[index].ID()
+	m.playerScroll, _, _ = playerWindow(len(players), m.playerScroll, index)
+
+	return m
+}
+
+/*
+moveSeat moves m's selected player by offset seats, keeping them selected.
+Moving past either end of the roster does nothing.
+*/
+func moveSeat(m model, offset int) (model, error) {
+	players := m.game.Players()
+	for i, player := range players {
+		if player.ID() != m.selectedPlayer || i+offset < 0 || i+offset >= len(players) {
+			continue
+		}
+
+		g, err := m.game.MovePlayer(m.selectedPlayer, offset)
+		if err != nil {
+			return m, err
+		}
+		return moveSelection(changeRoster(m, g), 0), nil
+	}
+
+	return m, nil
+}
+
+func updateRosterState(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	var (
+		cmd tea.Cmd
+		err error
+	)
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.roster.Done):
+			m.selectedPlayer = 0
+			m.state = mainState
+		case key.Matches(msg, m.keys.roster.Edit):
+			if player, ok := m.game.Player(m.selectedPlayer); ok {
+				m, cmd = startPlayerForm(m, player)
+				m.state = editPlayerState
+			}
+		case key.Matches(msg, m.keys.roster.Remove):
+			// the player after the removed one takes their seat and stays selected, or the one before from the last seat
+			removed := m.selectedPlayer
+			if m = moveSelection(m, 1); m.selectedPlayer == removed {
+				m = moveSelection(m, -1)
+			}
+			var g game.Game
+			if g, err = m.game.RemovePlayer(removed); err != nil {
+				break
+			}
+			if m = moveSelection(changeRoster(m, g), 0); m.game.PlayerCount() == 0 {
+				m.state = mainState
+			}
+		case key.Matches(msg, m.keys.roster.MoveDown):
+			m, err = moveSeat(m, 1)
+		case key.Matches(msg, m.keys.roster.MoveUp):
+			m, err = moveSeat(m, -1)
+		case key.Matches(msg, m.keys.roster.Shuffle):
+			var g game.Game
+			if g, err = m.game.ShufflePlayers(); err == nil {
+				m = moveSelection(changeRoster(m, g), 0)
+			}
+		case key.Matches(msg, m.keys.roster.YoungestFirst):
+			var g game.Game
+			if g, err = m.game.SeatYoungestFirst(); err == nil {
+				m = moveSelection(changeRoster(m, g), 0)
+			}
+		case key.Matches(msg, m.keys.roster.History):
+			m = stepHistory(m, msg)
+		case key.Matches(msg, m.keys.roster.NextPlayer):
+			m = moveSelection(m, 1)
+		case key.Matches(msg, m.keys.roster.PreviousPlayer):
+			m = moveSelection(m, -1)
+		}
+	}
+
+	if err != nil {
+		m.err = err
+		m.selectedPlayer = 0
+		m.state = errorState
+	}
+
+	return m, cmd
+}
+
+func viewRosterState(m model) string {
+	return m.layout.assemble(m.theme, renderPlayers(m, m.selectedPlayer), renderHelpContent(m, m.keys.roster), m.turnView.View())
+}
@@ -0,0 +1,206 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/bmoller/cherry-o/game"
+)
+
+type scoreKeeperKeyMap struct {
+	History      key.Binding
+	NextFace     key.Binding
+	PreviousFace key.Binding
+	Quit         key.Binding
+	Submit       key.Binding
+}
+
+func (k scoreKeeperKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Submit, k.Quit}
+}
+
+func (k scoreKeeperKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.PreviousFace, k.NextFace},
+		{k.Submit, k.Quit, k.History},
+	}
+}
+
+var scoreKeeperKeyBinds = scoreKeeperKeyMap{
+	History: historyKeyBinds.combined("Undo/redo"),
+	NextFace: key.NewBinding(
+		key.WithHelp("↓", "Next face"),
+		key.WithKeys("down"),
+	),
+	PreviousFace: key.NewBinding(
+		key.WithHelp("↑", "Previous face"),
+		key.WithKeys("up"),
+	),
+	Quit: key.NewBinding(
+		key.WithHelp("esc", "Stop game"),
+		key.WithKeys("esc"),
+	),
+	Submit: key.NewBinding(
+		key.WithHelp("enter", "Record spin"),
+		key.WithKeys("enter"),
+	),
+}
+
+type facesDelegate struct{}
+
+func (f facesDelegate) Height() int {
+	return 1
+}
+
+func (f facesDelegate) Spacing() int {
+	return 0
+}
+
+func (f facesDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd {
+	return nil
+}
+
+func (f facesDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	face, ok := item.(game.Face)
+	if !ok {
+		return
+	}
+
+	if index == m.Index() {
+		fmt.Fprint(w, " > ", face.Symbol, " ", face.Name)
+	} else {
+		fmt.Fprint(w, "   ", face.Symbol, " ", face.Name)
+	}
+}
+
+var (
+	scoreKeeperTitle = lipgloss.NewStyle().
+				Border(lipgloss.ThickBorder(), false, false, true)
+	styleScoreKeeper = lipgloss.NewStyle().
+				Border(lipgloss.NormalBorder(), true).
+				Padding(1, 2)
+)
+
+/*
+startScoreKeeper begins keeping score for m's roster, offering the faces of its spinner to choose from.
+*/
+func startScoreKeeper(m model) (model, tea.Cmd, error) {
+	if m.game.PlayerCount() == 0 {
+		return m, nil, errors.New("add a player before playing")
+	}
+
+	var faces []list.Item
+	for _, face := range m.game.Rules().Faces {
+		faces = append(faces, face)
+	}
+	cmd := m.faceList.SetItems(faces)
+	m.faceList.ResetSelected()
+	m = fitLists(m)
+
+	m = forgetScoring(m)
+	m.match = m.game
+	m.playback = playback{generation: m.playback.generation}
+	m.winner = game.Player{}
+	m.state = scoreKeeperState
+	m = refreshMatch(m)
+
+	return m, cmd, nil
+}
+
+/*
+recordFace takes the current player's turn with the face selected in m.
+When the turn wins the game m returns to the mainState.
+*/
+func recordFace(m model) model {
+	var (
+		err  error
+		over bool
+		turn game.Turn
+	)
+	before := takeSnapshot(m)
+
+	face, ok := m.faceList.SelectedItem().(game.Face)
+	if !ok {
+		return m
+	}
+	if m.match, turn, over, err = m.match.ApplyFace(face); err != nil {
+		m.err = err
+		m.state = errorState
+		return m
+	}
+	m = remember(m, before)
+	m.playback.turns = append(m.playback.turns[:len(m.playback.turns):len(m.playback.turns)], turn)
+	m.playback.shown = len(m.playback.turns)
+	m.faceList.ResetSelected()
+
+	if over {
+		m.winner, _ = m.match.Winner()
+		m.state = mainState
+	}
+	m = refreshMatch(m)
+	m.turnView.GotoBottom()
+
+	return m
+}
+
+func updateScoreKeeperState(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.scoreKeeper.Submit):
+			m = recordFace(m)
+		case key.Matches(msg, m.keys.scoreKeeper.History):
+			m = stepHistory(m, msg)
+		case key.Matches(msg, m.keys.scoreKeeper.Quit):
+			m = forgetScoring(m)
+			m.match = game.Game{}
+			m.state = mainState
+			m = refreshMatch(m)
+			m.turnView.GotoBottom()
+		case key.Matches(msg, m.keys.scoreKeeper.NextFace):
+			m.faceList.CursorDown()
+		case key.Matches(msg, m.keys.scoreKeeper.PreviousFace):
+			m.faceList.CursorUp()
+		}
+	}
+
+	return m, nil
+}
+
+func viewScoreKeeperState(m model) string {
+	var (
+		current game.PlayerID
+		title   string
+	)
+	if player, ok := m.match.CurrentPlayer(); ok {
+		current = player.ID()
+		title = m.theme.playerStyle(player.Color()).Inherit(scoreKeeperTitle).BorderForeground(m.theme.Accent.color()).Render(fmt.Sprintf("What did %s spin?", spokenName(m, player)))
+	}
+
+	var last string
+	if turns := m.playback.turns; len(turns) > 0 {
+		turn := turns[len(turns)-1]
+		last = m.theme.playerStyle(turn.Player.Color()).Render(turn.Face.Symbol + " " + narrateTurn(m, turn))
+	}
+
+	scoreKeeperContent := lipgloss.JoinVertical(
+		lipgloss.Center,
+		title,
+		"",
+		m.faceList.View(),
+	)
+
+	scoreKeeperPlacement := lipgloss.Place(m.layout.mainWidth, m.layout.mainHeight,
+		lipgloss.Center, lipgloss.Center,
+		lipgloss.JoinVertical(lipgloss.Center, last, "", styleScoreKeeper.Render(scoreKeeperContent)),
+		lipgloss.WithWhitespaceChars("-"),
+		lipgloss.WithWhitespaceForeground(m.theme.Accent.color()))
+
+	return m.layout.assemble(m.theme, renderPlayers(m, current), renderHelpContent(m, m.keys.scoreKeeper), scoreKeeperPlacement)
+}
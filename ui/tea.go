@@ -1,9 +1,13 @@
 package ui
 
 import (
+	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
@@ -11,6 +15,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/bmoller/cherry-o/game"
+	"github.com/bmoller/cherry-o/game/replay"
 )
 
 /*
@@ -29,8 +34,16 @@ const (
 	errorState
 	// addPlayerState shows components used to add a new player to the game.
 	addPlayerState
-	// removePlayerState handles removal of an existing player from the game, assuming there are any.
-	removePlayerState
+	// rosterState lets the user choose a player to edit or remove and change the order of seats, assuming there are any players.
+	rosterState
+	// editPlayerState renames or recolors the chosen player, reusing the components of addPlayerState.
+	editPlayerState
+	// playbackState reveals the turns of a game one at a time, with controls for pausing and speed.
+	playbackState
+	// hotSeatState lets each player spin for themselves, one keypress per turn.
+	hotSeatState
+	// scoreKeeperState records the faces spun on a physical spinner, keeping score without any randomness.
+	scoreKeeperState
 )
 
 /*
@@ -43,8 +56,16 @@ func (s appState) update(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 		return updateErrorState(msg, m)
 	case addPlayerState:
 		return updateAddPlayerState(msg, m)
-	case removePlayerState:
-		return updateRemovePlayerState(msg, m)
+	case rosterState:
+		return updateRosterState(msg, m)
+	case editPlayerState:
+		return updateEditPlayerState(msg, m)
+	case playbackState:
+		return updatePlaybackState(msg, m)
+	case hotSeatState:
+		return updateHotSeatState(msg, m)
+	case scoreKeeperState:
+		return updateScoreKeeperState(msg, m)
 	default:
 		return updateMainState(msg, m)
 	}
@@ -60,14 +81,50 @@ func (s appState) view(m model) string {
 		return viewErrorState(m)
 	case addPlayerState:
 		return viewAddPlayerState(m)
-	case removePlayerState:
-		return viewRemovePlayerState(m)
+	case rosterState:
+		return viewRosterState(m)
+	case editPlayerState:
+		return viewEditPlayerState(m)
+	case playbackState:
+		return viewPlaybackState(m)
+	case hotSeatState:
+		return viewHotSeatState(m)
+	case scoreKeeperState:
+		return viewScoreKeeperState(m)
 	default:
 		return viewMainState(m)
 	}
 }
 
+/*
+keyMap returns the bindings that the appState shows in the help pane, from those of m.
+*/
+func (s appState) keyMap(m model) help.KeyMap {
+	switch s {
+	case errorState:
+		return m.keys.error
+	case addPlayerState, editPlayerState:
+		return m.keys.addPlayer
+	case rosterState:
+		return m.keys.roster
+	case playbackState:
+		return m.keys.playback
+	case hotSeatState:
+		return m.keys.hotSeat
+	case scoreKeeperState:
+		return m.keys.scoreKeeper
+	default:
+		return m.keys.main
+	}
+}
+
 type model struct {
+	// Whether players are set apart by markers and named colors as well as by color itself.
+	accessible bool
+	// Used to query a player's age, which is optional, when adding or editing them.
+	ageInput textinput.Model
+	// Whether to draw the board from plain ASCII rather than emoji.
+	ascii bool
 	// Used to display the current state's keybinds.
 	bindHelp help.Model
 	// Presents available colors to the user when adding a new player.
@@ -76,8 +133,32 @@ type model struct {
 	err error
 	// An embedded game simulation; its outputs are presented to the user via the model.
 	game game.Game
+	// Presents the spinner's faces when keeping score of a game played with a physical spinner.
+	faceList list.Model
+	// Changes to the roster and spins when keeping score, which can be undone and redone.
+	history history
+	// The game being played turn by turn in the hotSeatState or scoreKeeperState.
+	match game.Game
+	// Seed of match's spinner in the hotSeatState, so that the game can be saved as a replay once it's over.
+	matchSeed int64
+	// Key bindings of every state, after any preset and keys file have been applied.
+	keys keyMaps
+	// Sizes of the panes and components, worked out from the size of the terminal.
+	layout layout
 	// Used to query the name when adding a new player.
 	nameInput textinput.Model
+	// The most recent game's turns as they are revealed, along with the speed and position of playback.
+	playback playback
+	// Where to save a replay of each game played, if anywhere.
+	recordPath string
+	// Index of the first player shown when the roster is too long for the players pane.
+	playerScroll int
+	// The player highlighted on the roster or being edited, if any; the zero ID means nobody is selected.
+	selectedPlayer game.PlayerID
+	// Whether the main pane shows the board of trees and buckets rather than the turn list.
+	showBoard bool
+	// Colors everything is drawn in.
+	theme Theme
 	// Tracks the current state of the application, which determines how to update and display.
 	state appState
 	// Presents the list of turns from the most recent round of play.
@@ -87,10 +168,66 @@ type model struct {
 }
 
 /*
-New creates and returns a model with defaults for a new execution.
+Options carries the settings chosen at launch, such as command-line flags, into a new model.
+*/
+type Options struct {
+	// The ruleset used for every game played in this session.
+	Rules game.Ruleset
+	// If set, each game played is saved as a replay at this path, replacing the previous one.
+	RecordPath string
+	// Draw the board from plain ASCII even if the terminal seems able to show emoji.
+	ASCII bool
+	// Name of the key binding preset to start from; if empty, the keys file's preset or the defaults.
+	// The keys file in the user's config directory can change individual bindings either way.
+	Keys string
+	// Set players apart without relying on color; also turned on by NO_COLOR or the settings file.
+	Accessible bool
+	// Name of a built-in theme or path of a theme file to draw with; if empty, the theme file in the user's config
+	// directory is used if there is one.
+	Theme string
+	// If set, the session starts with this replay's roster and rules and shows its turns; Rules is ignored.
+	Replay *replay.Replay
+}
+
+/*
+errMsg reports a failure from a command so that it can be shown in the errorState.
+*/
+type errMsg struct {
+	err error
+}
+
+/*
+New creates and returns a model with defaults for a new execution, configured by opts.
 */
-func New() tea.Model {
-	colorList := list.New(nil, colorsDelegate{}, 10, 6)
+func New(opts Options) (tea.Model, error) {
+	g, err := game.New(opts.Rules)
+	if opts.Replay != nil {
+		g, err = opts.Replay.Game, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	theme, err := chooseTheme(opts.Theme)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := loadSettings()
+	if err != nil {
+		return nil, err
+	}
+	keys, err := chooseKeys(opts.Keys)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		accessible = opts.Accessible || accessibleRequested() || settings.Accessible
+		ascii      = opts.ASCII || !emojiSupported()
+		delegate   = colorsDelegate{theme: theme}
+	)
+
+	colorList := list.New(nil, delegate, 10, 0)
 	colorList.Title = "Select a Color"
 	for _, function := range []func(bool){
 		colorList.SetFilteringEnabled,
@@ -102,32 +239,62 @@ func New() tea.Model {
 		function(false)
 	}
 	colorList.SetStatusBarItemName("color", "colors")
-	colorList.SetWidth(26 - 3) // content width after padding, minus prefix
+
+	faceList := list.New(nil, facesDelegate{}, 10, 0)
+	for _, function := range []func(bool){
+		faceList.SetFilteringEnabled,
+		faceList.SetShowFilter,
+		faceList.SetShowHelp,
+		faceList.SetShowPagination,
+		faceList.SetShowStatusBar,
+		faceList.SetShowTitle,
+	} {
+		function(false)
+	}
 
 	helpModel := help.New()
 	helpModel.ShowAll = true
-	helpModel.Width = 36
+	helpModel.Styles = theme.helpStyles()
 
 	nameInput := textinput.New()
-	nameInput.Width = 26 - 3 // content width after padding, minus prefix
+	nameInput.Placeholder = "Name"
+	ageInput := textinput.New()
+	ageInput.Placeholder = "Age (optional)"
+	ageInput.CharLimit = 3
 
-	viewportModel := viewport.New(74, 50)
+	viewportModel := viewport.New(0, 0)
 	viewportModel.Style = viewportModel.Style.Copy().Padding(1, 2)
 
-	return model{
-		bindHelp:  helpModel,
-		colorList: colorList,
-		game:      game.Game{},
-		nameInput: nameInput,
-		state:     mainState,
-		turnView:  viewportModel,
+	m := model{
+		accessible: accessible,
+		ageInput:   ageInput,
+		ascii:      ascii,
+		bindHelp:   helpModel,
+		colorList:  colorList,
+		faceList:   faceList,
+		game:       g,
+		keys:       keys,
+		nameInput:  nameInput,
+		recordPath: opts.RecordPath,
+		state:      mainState,
+		theme:      theme,
+		turnView:   viewportModel,
 	}
+	m = resize(m, defaultWidth, defaultHeight)
+	if opts.Replay != nil {
+		m, _ = startPlayback(m, *opts.Replay)
+	}
+
+	return m, nil
 }
 
 /*
-Init meets the requirements of the tea.Model interface but is currently a no-op.
+Init meets the requirements of the tea.Model interface; it starts playback if the model begins with a replay.
 */
 func (m model) Init() tea.Cmd {
+	if m.state == playbackState {
+		return playbackTick(m.playback)
+	}
 	return nil
 }
 
@@ -140,10 +307,18 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, tea.Quit
 	}
 
+	if errMsg, ok := msg.(errMsg); ok {
+		m.err = errMsg.err
+		m.state = errorState
+		return m, nil
+	}
+
 	if sizeMsg, ok := msg.(tea.WindowSizeMsg); ok {
-		newHeight := sizeMsg.Height - 15
-		mainPane = mainPane.Copy().Height(newHeight)
-		m.turnView.Height = newHeight
+		m = resize(m, sizeMsg.Width, sizeMsg.Height)
+	}
+
+	if mouseMsg, ok := msg.(tea.MouseMsg); ok && !m.layout.tooSmall {
+		return updateMouse(mouseMsg, m)
 	}
 
 	return m.state.update(msg, m)
@@ -153,6 +328,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 View renders the current state per requirements of the tea.Model interface.
 */
 func (m model) View() string {
+	if m.layout.tooSmall {
+		return viewTooSmall(m.layout, m.theme)
+	}
 	return m.state.view(m)
 }
 
@@ -160,78 +338,240 @@ func (m model) View() string {
 renderHelpContent renders keyMap's view in the help view component of m.
 */
 func renderHelpContent(m model, keyMap help.KeyMap) string {
-	return lipgloss.JoinVertical(
+	return lipgloss.PlaceHorizontal(m.layout.helpWidth, lipgloss.Center, lipgloss.JoinVertical(
 		lipgloss.Center,
 		helpTitle,
 		m.bindHelp.View(keyMap),
+	))
+}
+
+/*
+helpBindingAt returns the binding whose entry in the help pane is at x and y within its content, when it shows keyMap.
+The entries are found the same way that the help component and renderHelpContent lay them out.
+*/
+func helpBindingAt(m model, keyMap help.KeyMap, x int, y int) (key.Binding, bool) {
+	var (
+		view  = m.bindHelp.View(keyMap)
+		width = lipgloss.Width(view)
+		// the help component puts a separator after every column
+		separator = lipgloss.Width(m.bindHelp.FullSeparator)
+		row       = y - lipgloss.Height(helpTitle)
 	)
+	if titleWidth := lipgloss.Width(helpTitle); titleWidth > width {
+		width = titleWidth
+	}
+	left := 0
+	if gap := m.layout.helpWidth - width; gap > 0 {
+		left = gap / 2
+	}
+
+	for _, group := range keyMap.FullHelp() {
+		var keysWidth, descWidth int
+		for _, binding := range group {
+			if w := lipgloss.Width(binding.Help().Key); w > keysWidth {
+				keysWidth = w
+			}
+			if w := lipgloss.Width(binding.Help().Desc); w > descWidth {
+				descWidth = w
+			}
+		}
+
+		columnWidth := keysWidth + 1 + descWidth
+		if x >= left && x < left+columnWidth {
+			if row >= 0 && row < len(group) {
+				return group[row], true
+			}
+			break
+		}
+		left += columnWidth + separator
+	}
+
+	return key.Binding{}, false
+}
+
+/*
+playerWindow works out which rows of a roster of count players fit in the players pane.
+It returns the visible range and whether a scroll indicator row is needed.
+The window starts at scroll but is moved as needed to include focus, if focus is a valid index.
+*/
+func playerWindow(count int, scroll int, focus int) (start int, end int, scrolling bool) {
+	if count <= playersPaneRows {
+		return 0, count, false
+	}
+
+	visible := playersPaneRows - 1 // leave a row for the scroll indicator
+	start = scroll
+	if focus >= 0 && focus < start {
+		start = focus
+	} else if focus >= start+visible {
+		start = focus - visible + 1
+	}
+	if start > count-visible {
+		start = count - visible
+	}
+	if start < 0 {
+		start = 0
+	}
+
+	return start, start + visible, true
+}
+
+/*
+scrollPlayers moves the players pane by offset rows, keeping it within the roster.
+*/
+func scrollPlayers(m model, offset int) model {
+	m.playerScroll, _, _ = playerWindow(m.game.PlayerCount(), m.playerScroll+offset, -1)
+	return m
+}
+
+/*
+standingOrder returns players ordered by the cherries they hold, most first, keeping seat order between equals.
+*/
+func standingOrder(players []game.Player, cherries map[game.PlayerID]int) []game.Player {
+	sorted := append([]game.Player(nil), players...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return cherries[sorted[i].ID()] > cherries[sorted[j].ID()]
+	})
+
+	return sorted
+}
+
+/*
+renderProgress draws a bar of scoreBarCells showing cherries out of target, followed by the count itself.
+*/
+func renderProgress(cherries int, target int, symbols boardSymbols) string {
+	filled := cherries * scoreBarCells / target
+	if filled > scoreBarCells {
+		filled = scoreBarCells
+	} else if filled < 0 {
+		filled = 0
+	}
+
+	return strings.Repeat(symbols.fill, filled) + strings.Repeat(symbols.track, scoreBarCells-filled) +
+		fmt.Sprintf(" %*d/%d", len(strconv.Itoa(target)), cherries, target)
+}
+
+/*
+listedPlayers returns m's players in the order the players pane lists them, along with the range of them shown in it
+and whether there are more than fit; see renderPlayers.
+*/
+func listedPlayers(m model, selected game.PlayerID) (players []game.Player, start int, end int, scrolling bool) {
+	players = m.game.Players()
+	if m.state != rosterState && m.state != editPlayerState {
+		players = standingOrder(players, m.playback.standings())
+	}
+
+	focus := -1
+	for i, player := range players {
+		if player.ID() == selected {
+			focus = i
+		}
+	}
+	start, end, scrolling = playerWindow(len(players), m.playerScroll, focus)
+
+	return players, start, end, scrolling
+}
+
+/*
+playerAt returns the player listed at row of the players pane's content, if any, when selected is highlighted.
+*/
+func playerAt(m model, selected game.PlayerID, row int) (game.Player, bool) {
+	players, start, end, _ := listedPlayers(m, selected)
+
+	// the title and its underline come first
+	index := start + row - 2
+	if index < start || index >= end {
+		return game.Player{}, false
+	}
+	return players[index], true
+}
+
+/*
+truncateName shortens name to fit in width cells, ending it with an ellipsis if anything had to go.
+Names are cut between characters and measured as drawn, so wide and multi-byte characters are never split.
+*/
+func truncateName(name string, width int) string {
+	if lipgloss.Width(name) <= width {
+		return name
+	}
+	if width < 1 {
+		return ""
+	}
+
+	var (
+		kept strings.Builder
+		used int
+	)
+	for _, r := range name {
+		// leave two cells for potential double-width rendering of the ellipsis
+		if used += lipgloss.Width(string(r)); used > width-2 {
+			break
+		}
+		kept.WriteRune(r)
+	}
+
+	return kept.String() + "…"
 }
 
 /*
 renderPlayers takes players and renders it for proper display.
+Each player's cherries so far are shown against the target score, and players are ranked by standing
+except on the roster and while editing a player, when they are kept in seat order.
 If m has a winner set that winner will be marked as such in the output.
-Additionally, if selected is a valid int from the range of players, the corresponding player will be highlighted.
+Additionally, if selected is the ID of a current player, such as the one whose turn it is, the corresponding player will be highlighted.
+Long rosters are windowed to fit the pane, scrolled by m's playerScroll and always keeping selected in view.
 */
-func renderPlayers(m model, selected int) string {
+func renderPlayers(m model, selected game.PlayerID) string {
 	var (
-		players = m.game.Players()
-		rows    []string
+		cherries                       = m.playback.standings()
+		players, start, end, scrolling = listedPlayers(m, selected)
+		rows                           []string
+		symbols                        = emojiSymbols
+		target                         = m.game.Rules().TargetScore
 	)
+	if m.ascii {
+		symbols = asciiSymbols
+	}
+	// content width after padding, minus the prefix and the progress bar with a space either side
+	nameWidth := m.layout.playersWidth - 3 - lipgloss.Width(renderProgress(target, target, symbols)) - 1
+	if nameWidth < 1 {
+		// a long count can crowd out the names entirely; there's still a cell for the ellipsis
+		nameWidth = 1
+	}
 
-	for i := 0; i < len(players); i++ {
+	for i := start; i < end; i++ {
 		var (
+			marker      = playerMarker(m, players[i])
 			name        string
-			playerColor lipgloss.Style
+			playerColor = m.theme.playerStyle(players[i].Color())
 			prefix      string
 		)
 
-		switch players[i].Color() {
-		case game.Blue:
-			playerColor = styleBlue
-		case game.Green:
-			playerColor = styleGreen
-		case game.Red:
-			playerColor = styleRed
-		case game.Yellow:
-			playerColor = styleYellow
-		}
+		name = marker + truncateName(players[i].Name, nameWidth-lipgloss.Width(marker))
 
-		if len(players[i].Name) <= (26 - 3) { // content width after padding, minus the prefix
-			name = players[i].Name
-		} else {
-			name = players[i].Name[:26-5] + "…" // leave two spaces for potential double-width rendering of ellipsis
-		}
-
-		if i == selected {
+		if players[i].ID() == selected {
 			prefix = " > "
-			playerColor = playerColor.Copy().Background(white)
-		} else if m.winner.Name == players[i].Name {
-			prefix = "👑 "
+			playerColor = playerColor.Copy().Background(m.theme.Highlight.color())
+		} else if m.winner.ID() != 0 && m.winner.ID() == players[i].ID() {
+			prefix = symbols.crown + strings.Repeat(" ", 3-lipgloss.Width(symbols.crown))
 		} else {
 			prefix = "   "
 		}
 
-		rows = append(rows, prefix+playerColor.Render(name))
+		gap := nameWidth - lipgloss.Width(name) + 1
+		if gap < 1 {
+			gap = 1
+		}
+		rows = append(rows, prefix+playerColor.Render(name)+
+			strings.Repeat(" ", gap)+
+			m.theme.playerStyle(players[i].Color()).Render(renderProgress(cherries[players[i].ID()], target, symbols)))
+	}
+
+	if scrolling {
+		rows = append(rows, fmt.Sprintf("   ↑↓ %d-%d of %d", start+1, end, len(players)))
 	}
 
 	return lipgloss.JoinVertical(lipgloss.Left,
-		playersTitle,
+		lipgloss.PlaceHorizontal(m.layout.playersWidth, lipgloss.Center, playersTitle.Copy().BorderForeground(m.theme.Border.color()).Render("Players")),
 		strings.Join(rows, "\n"))
 }
-
-/*
-assembleView puts the content pieces together according to the designed layout.
-The playersContent, helpContent, and mainContent will each be placed in their appropriate locations in the view.
-appStates simply need to determine what they want placed in each pane and pass the content to this function.
-*/
-func assembleView(playersContent string, helpContent string, mainContent string) string {
-	return lipgloss.JoinVertical(
-		lipgloss.Left,
-		mainPane.Render(mainContent),
-		lipgloss.JoinHorizontal(
-			lipgloss.Top,
-			playersPane.Render(playersContent),
-			helpPane.Render(helpContent),
-		),
-	)
-}
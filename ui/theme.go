@@ -0,0 +1,307 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/bmoller/cherry-o/game"
+)
+
+// Name of the file in the user's config directory that holds a custom theme.
+const themeFile = "theme.json"
+
+/*
+ThemeColor is a color that may differ between terminals with light and dark backgrounds;
+lipgloss picks the variant to use from the terminal's background.
+Colors are ANSI numbers or hex codes, as with lipgloss.Color.
+In JSON a ThemeColor is either a single color used for both, or an object with "light" and "dark" colors.
+*/
+type ThemeColor struct {
+	Light string `json:"light"`
+	Dark  string `json:"dark"`
+}
+
+/*
+sameColor returns a ThemeColor that is c on both light and dark backgrounds.
+*/
+func sameColor(c string) ThemeColor {
+	return ThemeColor{Light: c, Dark: c}
+}
+
+func (c ThemeColor) color() lipgloss.TerminalColor {
+	return lipgloss.AdaptiveColor{Light: c.Light, Dark: c.Dark}
+}
+
+func (c ThemeColor) MarshalJSON() ([]byte, error) {
+	if c.Light == c.Dark {
+		return json.Marshal(c.Light)
+	}
+
+	type variants ThemeColor
+	return json.Marshal(variants(c))
+}
+
+func (c *ThemeColor) UnmarshalJSON(data []byte) error {
+	if data = bytes.TrimSpace(data); len(data) > 0 && data[0] == '"' {
+		var single string
+		if err := json.Unmarshal(data, &single); err != nil {
+			return err
+		}
+		*c = sameColor(single)
+		return nil
+	}
+
+	type variants ThemeColor
+	var decoded variants
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	if decoded.Light == "" || decoded.Dark == "" {
+		return fmt.Errorf("a color needs both light and dark variants; got %q and %q", decoded.Light, decoded.Dark)
+	}
+
+	*c = ThemeColor(decoded)
+	return nil
+}
+
+/*
+Theme is the set of colors the interface is drawn with.
+*/
+type Theme struct {
+	Name string `json:"name"`
+	// Fills the space around dialogs and underlines their titles.
+	Accent ThemeColor `json:"accent"`
+	// Borders of the main and players panes, and the underline of the players title.
+	Border ThemeColor `json:"border"`
+	// Border and text of error messages.
+	Error ThemeColor `json:"error"`
+	// Border of the help pane, and the keys and descriptions within it.
+	HelpBorder ThemeColor `json:"helpBorder"`
+	HelpDesc   ThemeColor `json:"helpDesc"`
+	HelpKey    ThemeColor `json:"helpKey"`
+	// Background of the highlighted player.
+	Highlight ThemeColor `json:"highlight"`
+	// Text belonging to players of each color.
+	Players map[game.Color]ThemeColor `json:"players"`
+}
+
+var (
+	ansiTheme = Theme{
+		Name:       "ansi",
+		Accent:     sameColor("3"),
+		Border:     sameColor("5"),
+		Error:      sameColor("1"),
+		HelpBorder: sameColor("2"),
+		// the help component's own defaults
+		HelpDesc:  ThemeColor{Light: "#B2B2B2", Dark: "#4A4A4A"},
+		HelpKey:   ThemeColor{Light: "#909090", Dark: "#626262"},
+		Highlight: sameColor("7"),
+		Players: map[game.Color]ThemeColor{
+			game.Blue:    sameColor("4"),
+			game.Green:   sameColor("2"),
+			game.Red:     sameColor("1"),
+			game.Yellow:  sameColor("3"),
+			game.Cyan:    sameColor("6"),
+			game.Magenta: sameColor("5"),
+			// Outside of the 16 ANSI colors; lipgloss picks the nearest match on terminals with fewer colors.
+			game.Orange: sameColor("208"),
+			game.Purple: sameColor("135"),
+		},
+	}
+
+	// https://ethanschoonover.com/solarized/; the accents are shared and the base tones swap between light and dark.
+	solarizedTheme = Theme{
+		Name:       "solarized",
+		Accent:     sameColor("#b58900"),
+		Border:     sameColor("#d33682"),
+		Error:      sameColor("#dc322f"),
+		HelpBorder: sameColor("#859900"),
+		HelpDesc:   ThemeColor{Light: "#93a1a1", Dark: "#586e75"},
+		HelpKey:    ThemeColor{Light: "#657b83", Dark: "#839496"},
+		Highlight:  ThemeColor{Light: "#eee8d5", Dark: "#073642"},
+		Players: map[game.Color]ThemeColor{
+			game.Blue:    sameColor("#268bd2"),
+			game.Green:   sameColor("#859900"),
+			game.Red:     sameColor("#dc322f"),
+			game.Yellow:  sameColor("#b58900"),
+			game.Cyan:    sameColor("#2aa198"),
+			game.Magenta: sameColor("#d33682"),
+			game.Orange:  sameColor("#cb4b16"),
+			game.Purple:  sameColor("#6c71c4"),
+		},
+	}
+
+	// Bright colors on dark backgrounds and dark ones on light backgrounds, for the most contrast either way.
+	highContrastTheme = Theme{
+		Name:       "high-contrast",
+		Accent:     ThemeColor{Light: "0", Dark: "15"},
+		Border:     ThemeColor{Light: "0", Dark: "15"},
+		Error:      ThemeColor{Light: "1", Dark: "9"},
+		HelpBorder: ThemeColor{Light: "0", Dark: "15"},
+		HelpDesc:   ThemeColor{Light: "0", Dark: "15"},
+		HelpKey:    ThemeColor{Light: "0", Dark: "15"},
+		Highlight:  ThemeColor{Light: "11", Dark: "4"},
+		Players: map[game.Color]ThemeColor{
+			game.Blue:    ThemeColor{Light: "4", Dark: "12"},
+			game.Green:   ThemeColor{Light: "22", Dark: "10"},
+			game.Red:     ThemeColor{Light: "124", Dark: "9"},
+			game.Yellow:  ThemeColor{Light: "94", Dark: "11"},
+			game.Cyan:    ThemeColor{Light: "23", Dark: "14"},
+			game.Magenta: ThemeColor{Light: "90", Dark: "13"},
+			game.Orange:  ThemeColor{Light: "130", Dark: "214"},
+			game.Purple:  ThemeColor{Light: "54", Dark: "177"},
+		},
+	}
+
+	builtinThemes = []Theme{ansiTheme, solarizedTheme, highContrastTheme}
+)
+
+/*
+ThemeNames lists the names of the built-in themes.
+*/
+func ThemeNames() []string {
+	names := make([]string, len(builtinThemes))
+	for i, theme := range builtinThemes {
+		names[i] = theme.Name
+	}
+	return names
+}
+
+/*
+BuiltinTheme returns the built-in theme called name, ignoring case.
+*/
+func BuiltinTheme(name string) (Theme, bool) {
+	for _, theme := range builtinThemes {
+		if strings.EqualFold(name, theme.Name) {
+			return theme.clone(), true
+		}
+	}
+	return Theme{}, false
+}
+
+func (t Theme) clone() Theme {
+	players := make(map[game.Color]ThemeColor, len(t.Players))
+	for color, themeColor := range t.Players {
+		players[color] = themeColor
+	}
+	t.Players = players
+
+	return t
+}
+
+/*
+ParseTheme decodes a theme from JSON.
+The theme starts as a copy of the built-in theme named by its "base" field, or the ANSI theme,
+so that it only needs to list the colors it changes.
+*/
+func ParseTheme(data []byte) (Theme, error) {
+	var header struct {
+		Base string `json:"base"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return Theme{}, err
+	}
+	if header.Base == "" {
+		header.Base = ansiTheme.Name
+	}
+
+	theme, ok := BuiltinTheme(header.Base)
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown base theme %q; expected one of %s", header.Base, strings.Join(ThemeNames(), ", "))
+	}
+	theme.Name = ""
+	if err := json.Unmarshal(data, &theme); err != nil {
+		return Theme{}, err
+	}
+	if theme.Name == "" {
+		theme.Name = "custom"
+	}
+
+	return theme, nil
+}
+
+/*
+LoadTheme reads a theme written as JSON from path; see ParseTheme.
+*/
+func LoadTheme(path string) (Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, err
+	}
+
+	theme, err := ParseTheme(data)
+	if err != nil {
+		return Theme{}, fmt.Errorf("invalid theme in %s: %s", path, err)
+	}
+	return theme, nil
+}
+
+/*
+chooseTheme returns the built-in theme called name or the theme in the file at name.
+If name is empty it returns the theme in the user's config directory, falling back to the ANSI theme.
+*/
+func chooseTheme(name string) (Theme, error) {
+	if name != "" {
+		if theme, ok := BuiltinTheme(name); ok {
+			return theme, nil
+		}
+
+		theme, err := LoadTheme(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			return Theme{}, fmt.Errorf("unknown theme %q; expected one of %s, or a theme file", name, strings.Join(ThemeNames(), ", "))
+		}
+		return theme, err
+	}
+
+	data, path, err := readConfig(themeFile)
+	switch {
+	case err != nil:
+		return Theme{}, err
+	case path == "":
+		return ansiTheme.clone(), nil
+	}
+
+	theme, err := ParseTheme(data)
+	if err != nil {
+		return Theme{}, fmt.Errorf("invalid theme in %s: %s", path, err)
+	}
+	return theme, nil
+}
+
+/*
+playerStyle returns the style used to render text belonging to a player of color c.
+*/
+func (t Theme) playerStyle(c game.Color) lipgloss.Style {
+	if themeColor, ok := t.Players[c]; ok {
+		return lipgloss.NewStyle().Foreground(themeColor.color())
+	}
+	return lipgloss.NewStyle()
+}
+
+/*
+helpStyles returns the styles of the help component in t's colors.
+*/
+func (t Theme) helpStyles() help.Styles {
+	var (
+		key  = lipgloss.NewStyle().Foreground(t.HelpKey.color())
+		desc = lipgloss.NewStyle().Foreground(t.HelpDesc.color())
+	)
+
+	return help.Styles{
+		Ellipsis:       desc.Copy(),
+		ShortKey:       key,
+		ShortDesc:      desc,
+		ShortSeparator: desc.Copy(),
+		FullKey:        key.Copy(),
+		FullDesc:       desc.Copy(),
+		FullSeparator:  desc.Copy(),
+	}
+}
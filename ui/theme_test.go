@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bmoller/cherry-o/game"
+)
+
+func TestThemeColorJSON(t *testing.T) {
+	testCases := map[string]struct {
+		data     string
+		expected ThemeColor
+	}{
+		"single":   {`"5"`, sameColor("5")},
+		"variants": {`{"light":"#002b36","dark":"#fdf6e3"}`, ThemeColor{Light: "#002b36", Dark: "#fdf6e3"}},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var decoded ThemeColor
+			if err := json.Unmarshal([]byte(test.data), &decoded); err != nil {
+				t.Fatalf("failed to decode %s: %s", test.data, err)
+			}
+			if decoded != test.expected {
+				t.Fatalf("expected %+v but got %+v", test.expected, decoded)
+			}
+
+			// a color is written back in the form it was read in
+			if encoded, err := json.Marshal(decoded); err != nil || string(encoded) != test.data {
+				t.Fatalf("expected %s when encoded but got %s", test.data, encoded)
+			}
+		})
+	}
+}
+
+func TestThemeColorJSONInvalid(t *testing.T) {
+	testCases := map[string]string{
+		"only light":  `{"light": "0"}`,
+		"only dark":   `{"dark": "15"}`,
+		"not a color": `5`,
+	}
+
+	for name, data := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var decoded ThemeColor
+			if err := json.Unmarshal([]byte(data), &decoded); err == nil {
+				t.Fatalf("shouldn't be able to decode %s", data)
+			}
+		})
+	}
+}
+
+func TestParseTheme(t *testing.T) {
+	theme, err := ParseTheme([]byte(`{"base": "solarized", "border": "1", "players": {"red": {"light": "88", "dark": "196"}}}`))
+	if err != nil {
+		t.Fatalf("failed to parse the theme: %s", err)
+	}
+
+	solarized, _ := BuiltinTheme("solarized")
+	switch {
+	case theme.Name != "custom":
+		t.Fatalf("expected an unnamed theme to be called custom but got %q", theme.Name)
+	case theme.Border != sameColor("1"):
+		t.Fatalf("expected the border to change to 1 but got %+v", theme.Border)
+	case theme.Accent != solarized.Accent:
+		t.Fatalf("expected the accent to stay %+v but got %+v", solarized.Accent, theme.Accent)
+	case theme.Players[game.Red] != ThemeColor{Light: "88", Dark: "196"}:
+		t.Fatalf("expected red players to change but got %+v", theme.Players[game.Red])
+	}
+	for _, color := range game.Colors() {
+		if color != game.Red && theme.Players[color] != solarized.Players[color] {
+			t.Fatalf("expected %s players to keep the base's %+v but got %+v", color, solarized.Players[color], theme.Players[color])
+		}
+	}
+
+	// the base itself is left alone
+	if again, _ := BuiltinTheme("solarized"); again.Players[game.Red] != solarized.Players[game.Red] {
+		t.Fatalf("parsing a theme changed its base; red players are now %+v", again.Players[game.Red])
+	}
+}
+
+func TestParseThemeDefaultBase(t *testing.T) {
+	theme, err := ParseTheme([]byte(`{"name": "mine", "error": "9"}`))
+	switch {
+	case err != nil:
+		t.Fatalf("failed to parse the theme: %s", err)
+	case theme.Name != "mine":
+		t.Fatalf("expected the theme to keep its name but got %q", theme.Name)
+	case theme.Border != ansiTheme.Border || theme.Players[game.Orange] != ansiTheme.Players[game.Orange]:
+		t.Fatalf("expected a theme without a base to start from the ANSI theme but got %+v", theme)
+	}
+}
+
+func TestParseThemeInvalid(t *testing.T) {
+	testCases := map[string]string{
+		"unknown base":   `{"base": "monokai"}`,
+		"half a color":   `{"accent": {"light": "0"}}`,
+		"unknown player": `{"players": {"teal": "6"}}`,
+		"not an object":  `"ansi"`,
+	}
+
+	for name, data := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseTheme([]byte(data)); err == nil {
+				t.Fatalf("shouldn't be able to parse %s", data)
+			}
+		})
+	}
+}